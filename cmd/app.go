@@ -0,0 +1,43 @@
+package main
+
+import (
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/storage/database"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"gorm.io/gorm"
+	"log"
+)
+
+// app holds the database and repositories the subcommands share.
+type app struct {
+	db        *gorm.DB
+	songs     repository.SongRepository
+	groups    repository.GroupRepository
+	jobs      repository.JobRepository
+	revisions repository.RevisionRepository
+}
+
+func connect() *app {
+	db := database.DbConnect()
+	logger.Info("database connect success")
+
+	return &app{
+		db:        db,
+		songs:     repository.NewSongRepository(db),
+		groups:    repository.NewGroupRepository(db),
+		jobs:      repository.NewJobRepository(db),
+		revisions: repository.NewRevisionRepository(db),
+	}
+}
+
+func newEnricher(client *infoapi.Client) enrichment.SongEnricher {
+	enricher, err := enrichment.NewFromConfig(config.AppConfig, client)
+	if err != nil {
+		log.Fatal("failed to configure song enrichment: ", err)
+	}
+	logger.Info("song enrichment configured", "providers", config.AppConfig.Enrichment.Providers)
+	return enricher
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/storage/database"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCheckConfig implements the `check-config` subcommand. It prints every
+// problem found and exits with 1 if there was any.
+func runCheckConfig(args []string) {
+	flags := flag.NewFlagSet("check-config", flag.ExitOnError)
+	checkDB := flags.Bool("db", false, "also connect to the database and look for pending migrations")
+	_ = flags.Parse(args)
+
+	problems := config.AppConfig.Validate()
+
+	if _, err := enrichment.NewFromConfig(config.AppConfig, infoapi.NewClient(config.AppConfig.ExternalAPI)); err != nil {
+		problems = append(problems, fmt.Errorf("enrichment: %w", err))
+	}
+
+	if *checkDB && len(problems) == 0 {
+		problems = append(problems, checkDatabase()...)
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, "error:", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("configuration is valid")
+}
+
+func checkDatabase() []error {
+	db := database.DbConnect()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return []error{fmt.Errorf("database: %w", err)}
+	}
+	ctx := context.Background()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return []error{fmt.Errorf("database: %w", err)}
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return []error{fmt.Errorf("migrations: %w", err)}
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("migrations: %w", err)}
+	}
+	if pending > 0 && !config.AppConfig.DB.AutoMigrate {
+		return []error{fmt.Errorf("%d migration(s) pending and DB_AUTO_MIGRATE is off, run migrate up", pending)}
+	}
+	return nil
+}
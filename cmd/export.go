@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/internal/storage/repository"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// runExport implements the `export` subcommand. Songs are written to stdout
+// unless -o is given.
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", "", "csv, json, ndjson or xlsx; taken from the -o extension when omitted, csv otherwise")
+	output := flags.String("o", "", "output file")
+	group := flags.String("group", "", "only songs of matching groups")
+	title := flags.String("song", "", "only songs with matching names")
+	exact := flags.Bool("exact", false, "match -group and -song exactly instead of as substrings")
+	sortSpec := flags.String("sort", "", "comma separated sort fields, - for descending: id, title, group, release_date, created_at, updated_at")
+	_ = flags.Parse(args)
+
+	format, ok := songio.ParseFormat(*formatName)
+	switch {
+	case ok:
+	case *formatName != "":
+		log.Fatal("invalid format, expected csv, json, ndjson or xlsx")
+	case *output != "":
+		if format, ok = songio.ParseFormat(*output); !ok {
+			log.Fatal("unknown file format, pass -format csv, json, ndjson or xlsx")
+		}
+	default:
+		format = songio.FormatCSV
+	}
+
+	sort, err := repository.ParseSongSort(*sortSpec)
+	if err != nil {
+		log.Fatal("invalid sort: ", err)
+	}
+	filter := repository.SongFilter{Group: *group, Title: *title, Exact: *exact, Sort: sort}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatal("failed to create export file: ", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	buffered := bufio.NewWriter(out)
+
+	writer, err := songio.NewRecordWriter(format, buffered)
+	if err != nil {
+		log.Fatal("failed to start export: ", err)
+	}
+
+	a := connect()
+	written, err := songio.NewExporter(a.songs).Export(context.Background(), filter, writer)
+	if err != nil {
+		// log.Fatal skips deferred calls, and the temporary files of an
+		// XLSX file would stay behind.
+		_ = writer.Abort()
+		log.Fatal("export failed: ", err)
+	}
+	if err := writer.Close(); err != nil {
+		log.Fatal("failed to finish export: ", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatal("failed to finish export: ", err)
+	}
+
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "exported %d song(s) to %s\n", written, *output)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/songio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+const importUsage = "usage: import [-format csv|json|ndjson] [-dry-run] [-enrich] <file|->"
+
+// runImport implements the `import` subcommand. The report is printed to
+// stdout as JSON; the exit code is 1 when the file could not be imported to
+// the end.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", "", "file format, taken from the file extension when omitted")
+	dryRun := flags.Bool("dry-run", false, "only validate and report what would be imported")
+	enrich := flags.Bool("enrich", false, "look up release date, text and link for records missing them")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatal(importUsage)
+	}
+	path := flags.Arg(0)
+
+	format, ok := songio.ParseFormat(*formatName)
+	if !ok {
+		if format, ok = songio.ParseFormat(path); !ok {
+			log.Fatal("unknown file format, pass -format csv, json or ndjson")
+		}
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal("failed to open import file: ", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	reader, err := songio.NewRecordReader(format, input)
+	if err != nil {
+		log.Fatal("failed to read import file: ", err)
+	}
+
+	a := connect()
+	// The enricher is only used with -enrich.
+	var enricher enrichment.SongEnricher = enrichment.NewNoopEnricher()
+	if *enrich {
+		enricher = newEnricher(infoapi.NewClient(config.AppConfig.ExternalAPI))
+	}
+	report, importErr := songio.NewImporter(a.songs, a.groups, enricher).Import(context.Background(), reader, songio.ImportOptions{
+		DryRun: *dryRun,
+		Enrich: *enrich,
+	})
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("failed to write import report: ", err)
+	}
+	if importErr != nil {
+		fmt.Fprintln(os.Stderr, "import stopped:", importErr)
+		os.Exit(1)
+	}
+}
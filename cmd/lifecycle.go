@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Exit codes of the commands that run servers.
+const (
+	exitOK = 0
+	// exitFailure means a server could not start or stopped by itself.
+	exitFailure = 1
+	// exitShutdownTimeout means requests were still running when the
+	// shutdown deadline passed and their connections were cut.
+	exitShutdownTimeout = 3
+)
+
+// serveUntilSignal runs the servers until SIGINT or SIGTERM arrives or one of
+// them fails. On a signal, draining is called and the servers go on for the
+// shutdown delay. They then stop accepting connections and get until the
+// shutdown timeout to finish the requests in flight. A second signal kills
+// the process right away. The result is the exit code.
+func serveUntilSignal(servers []*http.Server, draining func()) int {
+	delay, timeout := config.AppConfig.Server.ShutdownDelay, config.AppConfig.Server.ShutdownTimeout
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			logger.Info("server listening", slog.String("addr", srv.Addr))
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("server on %s: %w", srv.Addr, err)
+			}
+		}()
+	}
+
+	code := exitOK
+	select {
+	case <-ctx.Done():
+		stop()
+		logger.Info("shutdown signal received", slog.Duration("delay", delay), slog.Duration("timeout", timeout))
+		if draining != nil {
+			draining()
+		}
+		time.Sleep(delay)
+	case err := <-failed:
+		stop()
+		logger.Error("server failed, shutting down", slog.Any("error", err))
+		code = exitFailure
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logger.Error("connections did not drain in time, closing them", slog.String("addr", srv.Addr), slog.Any("error", err))
+				_ = srv.Close()
+				mu.Lock()
+				if code == exitOK {
+					code = exitShutdownTimeout
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	logger.Info("servers stopped")
+	return code
+}
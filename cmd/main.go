@@ -2,13 +2,41 @@ package main
 
 import (
 	"effectiveMobileTask/config"
-	"effectiveMobileTask/internal/mock"
-	"effectiveMobileTask/internal/routes"
-	"effectiveMobileTask/internal/storage/database"
 	"effectiveMobileTask/lib/logger"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
 )
 
+const usage = `usage: %[1]s [command] [flags]
+
+commands:
+  serve          start the API server (default)
+  migrate        apply, roll back or list database migrations
+  import         import songs from a CSV, JSON or NDJSON file
+  export         export songs to a CSV, JSON, NDJSON or XLSX file
+  reenrich       queue enrichment again for songs added since a date
+  seed           add generated songs for development
+  mock-server    start only the mock info API server
+  check-config   validate the configuration
+
+Run %[1]s <command> -h for the flags of a command.
+`
+
+var commands = map[string]func(args []string){
+	"serve":        runServe,
+	"migrate":      runMigrate,
+	"import":       runImport,
+	"export":       runExport,
+	"reenrich":     runReenrich,
+	"seed":         runSeed,
+	"mock-server":  runMockServer,
+	"check-config": runCheckConfig,
+}
+
 // @title Music Library API
 // @version 1.0
 // @description API for managing song information
@@ -20,17 +48,49 @@ import (
 // @license.name Apache 2.0
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 func main() {
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Printf(usage, filepath.Base(os.Args[0]))
+		return
+	}
+	run, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		fmt.Fprintf(os.Stderr, usage, filepath.Base(os.Args[0]))
+		os.Exit(2)
+	}
+	// Other commands may write their results to stdout.
+	if command != "serve" {
+		logger.Logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	}
+
 	config.LoadConfigEnv()
+	// check-config reports a bad LOG_* value along with the other problems.
+	if err := setupLogger(command); err != nil && command != "check-config" {
+		log.Fatal(err)
+	}
 	logger.Info("environment variables loaded")
 
-	db := database.DbConnect()
-	logger.Info("database connect success")
-
-	database.Migrate(db)
-	logger.Info("database migrate success")
-
-	go mock.MockServer()
-	logger.Info("mock server start success")
+	run(args)
+}
 
-	log.Fatal(routes.Router().Run(":" + config.AppConfig.Server.Port))
+// setupLogger applies the LOG_* settings. Unless LOG_OUTPUT says otherwise,
+// serve logs to stdout and the other commands to stderr.
+func setupLogger(command string) error {
+	var output io.Writer = os.Stdout
+	switch target := config.AppConfig.Log.Output; {
+	case target == "stderr", target == "" && command != "serve":
+		output = os.Stderr
+	case target == "stdout", target == "":
+	default:
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("open log file: %w", err)
+		}
+		output = file
+	}
+	return logger.Setup(config.AppConfig.Log.Level, config.AppConfig.Log.Format, output)
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/internal/storage/database"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down [-steps N] | status"
+
+// runMigrate implements the `migrate up|down|status` subcommand.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	migrator, err := database.NewMigrator(database.DbConnect())
+	if err != nil {
+		log.Fatal("failed to load migrations: ", err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal("migrate up failed: ", err)
+		}
+		fmt.Printf("applied %d migration(s)\n", applied)
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		_ = flags.Parse(args[1:])
+
+		rolledBack, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			log.Fatal("migrate down failed: ", err)
+		}
+		fmt.Printf("rolled back %d migration(s)\n", rolledBack)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("migrate status failed: ", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		_ = w.Flush()
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
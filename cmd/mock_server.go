@@ -0,0 +1,24 @@
+package main
+
+import (
+	"effectiveMobileTask/internal/mock"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+)
+
+// runMockServer implements the `mock-server` subcommand, which serves the
+// info API from the enrichment catalog on SERVER_MOCK_SERVER_PORT.
+func runMockServer(args []string) {
+	flags := flag.NewFlagSet("mock-server", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	shutdownTracing := setupTracing()
+	server, err := mock.NewServer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	code := serveUntilSignal([]*http.Server{server}, nil)
+	os.Exit(max(code, shutdownTracing()))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+const reenrichUsage = "usage: reenrich -since DATE [-failed] [-dry-run]"
+
+type reenrichOptions struct {
+	since      time.Time
+	onlyFailed bool
+	dryRun     bool
+}
+
+// runReenrich implements the `reenrich` subcommand. It queues enrichment
+// jobs for the songs added since the given time; the workers of a running
+// server pick them up and overwrite the song details with fresh ones.
+func runReenrich(args []string) {
+	opts, err := parseReenrichArgs(args, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !opts.dryRun && config.AppConfig.Enrichment.Workers == 0 {
+		log.Fatal("ENRICHMENT_WORKERS is 0, so the queued jobs would never run")
+	}
+
+	a := connect()
+	queued, err := reenrich(context.Background(), a.songs, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if opts.dryRun {
+		fmt.Printf("%d song(s) would be re-enriched\n", queued)
+		return
+	}
+	fmt.Printf("queued %d enrichment job(s)\n", queued)
+}
+
+// parseReenrichArgs reads the flags of reenrich. Usage and flag errors are
+// printed to output; -h returns flag.ErrHelp.
+func parseReenrichArgs(args []string, output io.Writer) (reenrichOptions, error) {
+	flags := flag.NewFlagSet("reenrich", flag.ContinueOnError)
+	flags.SetOutput(output)
+	sinceValue := flags.String("since", "", "songs added at or after this time: DD.MM.YYYY, YYYY-MM-DD or RFC 3339")
+	onlyFailed := flags.Bool("failed", false, "only songs whose enrichment failed")
+	dryRun := flags.Bool("dry-run", false, "only count the songs")
+	if err := flags.Parse(args); err != nil {
+		return reenrichOptions{}, err
+	}
+
+	if *sinceValue == "" || flags.NArg() > 0 {
+		return reenrichOptions{}, errors.New(reenrichUsage)
+	}
+	since, err := parseSince(*sinceValue)
+	if err != nil {
+		return reenrichOptions{}, err
+	}
+	return reenrichOptions{since: since, onlyFailed: *onlyFailed, dryRun: *dryRun}, nil
+}
+
+// reenrich queues a job for every matching song and marks the song as
+// pending along with it. It returns the number of songs queued, or that
+// would be in a dry run.
+func reenrich(ctx context.Context, songs repository.SongRepository, opts reenrichOptions) (int, error) {
+	filter := repository.SongFilter{CreatedFrom: &opts.since, Sort: repository.DefaultSongSort, Limit: 500}
+	queued := 0
+	for {
+		page, err := songs.List(ctx, filter)
+		if err != nil {
+			return queued, fmt.Errorf("failed to list songs: %w", err)
+		}
+
+		for _, song := range page {
+			if opts.onlyFailed && song.EnrichmentStatus != models.EnrichmentStatusFailed {
+				continue
+			}
+			if !opts.dryRun {
+				job := models.EnrichmentJob{SongID: song.ID, Group: song.GroupName, Song: song.Title}
+				err := songs.QueueEnrichment(ctx, &job)
+				if errors.Is(err, repository.ErrNotFound) {
+					// Deleted since it was listed.
+					continue
+				}
+				if err != nil {
+					return queued, fmt.Errorf("failed to enqueue enrichment job: %w", err)
+				}
+			}
+			queued++
+		}
+
+		if len(page) < filter.Limit {
+			return queued, nil
+		}
+		cursor := repository.NewSongCursor(page[len(page)-1], filter.Sort, false)
+		filter.Cursor = &cursor
+	}
+}
+
+func parseSince(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "02.01.2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid -since %q, expected DD.MM.YYYY, YYYY-MM-DD or RFC 3339", value)
+}
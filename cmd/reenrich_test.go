@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"errors"
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"01.09.2024", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-09-01", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-09-01T12:30:00+03:00", time.Date(2024, 9, 1, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "31.02.2024", "2024-09-01 12:30", "09/01/2024"} {
+		if _, err := parseSince(value); err == nil {
+			t.Errorf("parseSince(%q) succeeded", value)
+		}
+	}
+}
+
+func TestParseReenrichArgs(t *testing.T) {
+	since := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    reenrichOptions
+		wantErr bool
+	}{
+		{name: "since", args: []string{"-since", "01.09.2024"}, want: reenrichOptions{since: since}},
+		{name: "all flags", args: []string{"-since=2024-09-01", "-failed", "-dry-run"}, want: reenrichOptions{since: since, onlyFailed: true, dryRun: true}},
+		{name: "no since", args: []string{"-failed"}, wantErr: true},
+		{name: "invalid since", args: []string{"-since", "someday"}, wantErr: true},
+		{name: "extra argument", args: []string{"-since", "01.09.2024", "now"}, wantErr: true},
+		{name: "unknown flag", args: []string{"-since", "01.09.2024", "-all"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReenrichArgs(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReenrichArgs(%q) error = %v, want error %t", tt.args, err, tt.wantErr)
+			}
+			if !got.since.Equal(tt.want.since) || got.onlyFailed != tt.want.onlyFailed || got.dryRun != tt.want.dryRun {
+				t.Errorf("parseReenrichArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseReenrichArgs([]string{"-h"}, io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("parseReenrichArgs(-h) error = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestReenrichMarksSongsPending(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	group, err := store.Groups().FirstOrCreate(ctx, "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := []string{models.EnrichmentStatusEnriched, models.EnrichmentStatusFailed}
+	for i, status := range statuses {
+		song := &models.Song{GroupId: group.ID, Title: []string{"Uprising", "Starlight"}[i], EnrichmentStatus: status}
+		if err := store.Songs().Create(ctx, song); err != nil {
+			t.Fatal(err)
+		}
+	}
+	since := time.Now().Add(-time.Hour)
+
+	queued, err := reenrich(ctx, store.Songs(), reenrichOptions{since: since, onlyFailed: true, dryRun: true})
+	if err != nil || queued != 1 {
+		t.Fatalf("dry run queued %d, %v, want 1", queued, err)
+	}
+	if _, err := store.Jobs().GetByID(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("dry run enqueued a job")
+	}
+
+	queued, err = reenrich(ctx, store.Songs(), reenrichOptions{since: since})
+	if err != nil || queued != 2 {
+		t.Fatalf("reenrich queued %d, %v, want 2", queued, err)
+	}
+	for id := uint(1); id <= 2; id++ {
+		song, err := store.Songs().GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if song.EnrichmentStatus != models.EnrichmentStatusPending || song.Version != 2 {
+			t.Errorf("song %d is %s at version %d, want pending at version 2", id, song.EnrichmentStatus, song.Version)
+		}
+		job, err := store.Jobs().GetByID(ctx, id)
+		if err != nil || job.SongID != id || job.Status != models.JobStatusQueued || job.Group != "Muse" {
+			t.Errorf("job %d = %+v, %v, want a queued job for song %d", id, job, err, id)
+		}
+	}
+
+	queued, err = reenrich(ctx, store.Songs(), reenrichOptions{since: time.Now().Add(time.Hour)})
+	if err != nil || queued != 0 {
+		t.Errorf("reenrich of future songs queued %d, %v, want 0", queued, err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/songio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+var (
+	seedAdjectives = []string{"Electric", "Silent", "Golden", "Broken", "Midnight", "Wild", "Crimson", "Hollow", "Neon", "Lonely", "Frozen", "Burning"}
+	seedNouns      = []string{"Hearts", "River", "Echo", "Horizon", "Machine", "Garden", "Signal", "Ocean", "Shadow", "Highway", "Satellite", "Mirror"}
+	seedWords      = []string{"night", "light", "fire", "rain", "road", "dream", "time", "home", "sky", "love", "city", "heart", "stars", "away", "again", "tonight"}
+)
+
+// runSeed implements the `seed` subcommand, which fills a development
+// database with generated songs. It goes through the importer, so songs that
+// already exist are skipped.
+func runSeed(args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	count := flags.Int("count", 100, "number of songs to generate")
+	groups := flags.Int("groups", 0, "number of groups to spread the songs over, count/10 by default")
+	seed := flags.Uint64("seed", 0, "random seed for reproducible data, random when 0")
+	_ = flags.Parse(args)
+
+	if *count < 1 {
+		log.Fatal("-count must be positive")
+	}
+	if *groups < 1 {
+		*groups = max(*count/10, 1)
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	a := connect()
+	reader := newSeedReader(*count, *groups, *seed)
+	report, err := songio.NewImporter(a.songs, a.groups, enrichment.NewNoopEnricher()).Import(context.Background(), reader, songio.ImportOptions{})
+	if err != nil {
+		log.Fatal("seeding failed: ", err)
+	}
+
+	fmt.Printf("added %d song(s), %d already existed (seed %d)\n", report.Created, report.Duplicates, *seed)
+}
+
+// seedReader generates records with unique group and song names.
+type seedReader struct {
+	rnd    *rand.Rand
+	groups []string
+	seen   map[string]bool
+	count  int
+	row    int
+}
+
+func newSeedReader(count, groups int, seed uint64) *seedReader {
+	r := &seedReader{rnd: rand.New(rand.NewPCG(seed, seed)), seen: make(map[string]bool), count: count}
+	for i := 0; i < groups; i++ {
+		r.groups = append(r.groups, fmt.Sprintf("The %s %s %d", r.pick(seedAdjectives), r.pick(seedNouns), i+1))
+	}
+	return r
+}
+
+func (r *seedReader) Next() (songio.Record, error) {
+	if r.row == r.count {
+		return songio.Record{}, io.EOF
+	}
+	r.row++
+
+	group := r.pick(r.groups)
+	title := r.pick(seedAdjectives) + " " + r.pick(seedNouns)
+	// Numbered titles once the word combinations of the group run out.
+	for n := 2; r.seen[group+"\x00"+title]; n++ {
+		title = fmt.Sprintf("%s %s %d", r.pick(seedAdjectives), r.pick(seedNouns), n)
+	}
+	r.seen[group+"\x00"+title] = true
+
+	released := time.Date(1960, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, r.rnd.IntN(365*64))
+	return songio.Record{
+		Row:         r.row,
+		Group:       group,
+		Song:        title,
+		ReleaseDate: released.Format("02.01.2006"),
+		Text:        r.lyrics(),
+		Link:        fmt.Sprintf("https://www.youtube.com/watch?v=seed%08x", r.rnd.Uint32()),
+	}, nil
+}
+
+func (r *seedReader) lyrics() string {
+	verses := make([]string, 2+r.rnd.IntN(3))
+	for i := range verses {
+		lines := make([]string, 4)
+		for j := range lines {
+			words := make([]string, 3+r.rnd.IntN(4))
+			for k := range words {
+				words[k] = r.pick(seedWords)
+			}
+			line := strings.Join(words, " ")
+			lines[j] = strings.ToUpper(line[:1]) + line[1:]
+		}
+		verses[i] = strings.Join(lines, "\n")
+	}
+	return strings.Join(verses, "\n\n")
+}
+
+func (r *seedReader) pick(values []string) string {
+	return values[r.rnd.IntN(len(values))]
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/health"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/metrics"
+	"effectiveMobileTask/internal/mock"
+	"effectiveMobileTask/internal/routes"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/internal/storage/database"
+	"effectiveMobileTask/internal/tracing"
+	"effectiveMobileTask/internal/worker"
+	"effectiveMobileTask/lib/logger"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+)
+
+// runServe implements the `serve` subcommand, the default one. On SIGINT or
+// SIGTERM it drains the HTTP servers, stops the background workers and
+// closes the database before exiting.
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	withMock := flags.Bool("mock", false, "also start the mock info API server")
+	_ = flags.Parse(args)
+
+	if problems := config.AppConfig.Validate(); len(problems) > 0 {
+		for _, problem := range problems {
+			logger.Error("invalid configuration", slog.Any("error", problem))
+		}
+		log.Fatal("invalid configuration, run check-config for details")
+	}
+
+	shutdownTracing := setupTracing()
+	a := connect()
+
+	if config.AppConfig.DB.AutoMigrate {
+		if err := database.Migrate(a.db); err != nil {
+			log.Fatal("database migration failed: ", err)
+		}
+		logger.Info("database migrate success")
+	}
+
+	infoClient := infoapi.NewClient(config.AppConfig.ExternalAPI)
+	enricher := newEnricher(infoClient)
+
+	enrichmentPool := worker.NewEnrichmentPool(a.jobs, a.songs, enricher, worker.EnrichmentPoolConfig{
+		Workers:      config.AppConfig.Enrichment.Workers,
+		PollInterval: config.AppConfig.Enrichment.PollInterval,
+		MaxAttempts:  config.AppConfig.Enrichment.MaxAttempts,
+		RetryDelay:   config.AppConfig.Enrichment.RetryDelay,
+		StaleAfter:   config.AppConfig.Enrichment.JobStaleAfter,
+	})
+	enrichmentPool.Start(context.Background())
+
+	trashPurger := worker.NewTrashPurger(a.songs, a.groups, config.AppConfig.Trash.Retention, config.AppConfig.Trash.PurgeInterval)
+	trashPurger.Start(context.Background())
+
+	songController := controllers.NewSongController(
+		a.songs,
+		a.groups,
+		a.revisions,
+		enricher,
+		controllers.SongControllerConfig{
+			AsyncEnrichment: config.AppConfig.Enrichment.Async,
+			TrashRetention:  config.AppConfig.Trash.Retention,
+			RequireIfMatch:  config.AppConfig.Server.RequireIfMatch,
+		},
+	)
+
+	if err := registerMetrics(a, infoClient, enricher); err != nil {
+		log.Fatal("failed to set up metrics: ", err)
+	}
+
+	checker, err := newHealthChecker(a, infoClient)
+	if err != nil {
+		log.Fatal("failed to set up health checks: ", err)
+	}
+
+	router := routes.Router(routes.Handlers{
+		Songs:  songController,
+		Jobs:   controllers.NewJobController(a.jobs),
+		Groups: controllers.NewGroupController(a.groups),
+		Import: controllers.NewImportController(songio.NewImporter(a.songs, a.groups, enricher)),
+		Export: controllers.NewExportController(songio.NewExporter(a.songs)),
+		Health: controllers.NewHealthController(checker),
+	})
+
+	servers := []*http.Server{{
+		Addr:         ":" + config.AppConfig.Server.Port,
+		Handler:      router,
+		ReadTimeout:  config.AppConfig.Server.ReadTimeout,
+		WriteTimeout: config.AppConfig.Server.WriteTimeout,
+		IdleTimeout:  config.AppConfig.Server.IdleTimeout,
+	}}
+	if *withMock {
+		mockServer, err := mock.NewServer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		servers = append(servers, mockServer)
+	}
+
+	code := serveUntilSignal(servers, checker.SetDraining)
+
+	enrichmentPool.Stop()
+	trashPurger.Stop()
+	code = max(code, shutdownTracing())
+	if err := database.Close(); err != nil {
+		logger.Error("failed to close database", slog.Any("error", err))
+		code = max(code, exitFailure)
+	}
+	logger.Info("shutdown complete", slog.Int("exit_code", code))
+	os.Exit(code)
+}
+
+// setupTracing installs the configured trace exporter. The returned function
+// flushes the remaining spans and returns the exit code to report.
+func setupTracing() func() int {
+	shutdown, err := tracing.Setup(context.Background(), config.AppConfig.Tracing)
+	if err != nil {
+		log.Fatal("failed to set up tracing: ", err)
+	}
+	return func() int {
+		ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.Error("failed to flush traces", slog.Any("error", err))
+			return exitFailure
+		}
+		return exitOK
+	}
+}
+
+// readinessTimeout bounds each readiness check, well below the usual probe
+// timeout of orchestrators.
+const readinessTimeout = 2 * time.Second
+
+func newHealthChecker(a *app, infoClient *infoapi.Client) (*health.Checker, error) {
+	sqlDB, err := a.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := database.NewMigrator(a.db)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []health.Check{health.DatabaseCheck(sqlDB), health.MigrationsCheck(migrator)}
+	if slices.Contains(config.AppConfig.Enrichment.Providers, enrichment.ProviderAPI) {
+		checks = append(checks, health.UpstreamCheck(infoClient))
+	}
+	return health.NewChecker(readinessTimeout, checks...), nil
+}
+
+func registerMetrics(a *app, infoClient *infoapi.Client, enricher enrichment.SongEnricher) error {
+	sqlDB, err := a.db.DB()
+	if err != nil {
+		return err
+	}
+	dbName := config.AppConfig.DB.Name
+	if dbName == "" {
+		dbName = "default"
+	}
+	metrics.RegisterDB(sqlDB, dbName)
+	metrics.RegisterCatalog(a.songs, a.groups)
+	metrics.RegisterCircuitState(func() float64 { return float64(infoClient.BreakerState()) })
+	if cache, ok := enricher.(*enrichment.CachedEnricher); ok {
+		metrics.RegisterCache(cache.Stats)
+	}
+	return nil
+}
@@ -2,40 +2,121 @@ package config
 
 import (
 	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
 	"github.com/joho/godotenv"
 	"log"
 	"log/slog"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	DB          DBConfig
 	Server      ServerConfig
 	ExternalAPI ExternalAPIConfig
+	Enrichment  EnrichmentConfig
+	Trash       TrashConfig
+	Tracing     TracingConfig
+	Log         LogConfig
 }
 
 type DBConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	URL      string
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	Name        string
+	URL         string
+	AutoMigrate bool
 }
 
 type ServerConfig struct {
 	Port           string
 	MockServerPort string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	// ShutdownDelay keeps serving, with readiness failing, for this long
+	// after a shutdown signal, so that load balancers stop sending traffic
+	// before connections are drained.
+	ShutdownDelay   time.Duration
+	ShutdownTimeout time.Duration
+	// RequireIfMatch rejects requests changing a song, such as PATCH and
+	// DELETE /songs/{id}, without an If-Match header. It is off by default,
+	// so that clients written before ETags keep working.
+	RequireIfMatch bool
 }
 
 type ExternalAPIConfig struct {
-	BaseURL string
-	InfoURL string
+	BaseURL          string
+	InfoURL          string
+	Timeout          time.Duration
+	MaxRetries       int
+	BackoffInitial   time.Duration
+	BackoffMax       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type EnrichmentConfig struct {
+	Providers       []string
+	CatalogPath     string
+	ReleaseDateFrom []string
+	TextFrom        []string
+	LinkFrom        []string
+	Async           bool
+	Workers         int
+	PollInterval    time.Duration
+	MaxAttempts     int
+	RetryDelay      time.Duration
+	JobStaleAfter   time.Duration
+	// CacheSize and CacheTTL bound the in-memory cache of enrichment
+	// lookups; either one at zero disables it. It is off by default, so
+	// that changes upstream show up right away.
+	CacheSize int
+	CacheTTL  time.Duration
+}
+
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter is none, otlp or stdout.
+	Exporter    string
+	ServiceName string
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint string
+	Insecure bool
+	// File receives the spans of the stdout exporter instead of stdout.
+	File        string
+	SampleRatio float64
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level string
+	// Format is json or text.
+	Format string
+	// Output is stdout, stderr or a file path. When empty, serve logs to
+	// stdout and the other commands to stderr.
+	Output string
 }
 
 var AppConfig Config
 
+// invalidValues collects environment values that could not be parsed and
+// were replaced by their defaults.
+var invalidValues []string
+
 func LoadConfigEnv() {
+	invalidValues = nil
 	if err := godotenv.Load(); err != nil {
 		logger.Error("not found .env file", slog.Any("err", err))
 		log.Fatal("Error loading .env file")
@@ -43,24 +124,121 @@ func LoadConfigEnv() {
 
 	AppConfig = Config{
 		DB: DBConfig{
-			Host:     getEnvOrDefault("DB_HOST", ""),
-			Port:     getEnvOrDefault("DB_PORT", ""),
-			User:     getEnvOrDefault("DB_USER", ""),
-			Password: getEnvOrDefault("DB_PASSWORD", ""),
-			Name:     getEnvOrDefault("DB_NAME", ""),
-			URL:      getEnvOrDefault("DATABASE_URL", ""),
+			Host:        getEnvOrDefault("DB_HOST", ""),
+			Port:        getEnvOrDefault("DB_PORT", ""),
+			User:        getEnvOrDefault("DB_USER", ""),
+			Password:    getEnvOrDefault("DB_PASSWORD", ""),
+			Name:        getEnvOrDefault("DB_NAME", ""),
+			URL:         getEnvOrDefault("DATABASE_URL", ""),
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
 		},
 		Server: ServerConfig{
-			Port:           getEnvOrDefault("SERVER_PORT", ""),
-			MockServerPort: getEnvOrDefault("SERVER_MOCK_SERVER_PORT", ""),
+			Port:            getEnvOrDefault("SERVER_PORT", ""),
+			MockServerPort:  getEnvOrDefault("SERVER_MOCK_SERVER_PORT", ""),
+			ReadTimeout:     getEnvDuration("SERVER_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout:    getEnvDuration("SERVER_WRITE_TIMEOUT", 5*time.Minute),
+			IdleTimeout:     getEnvDuration("SERVER_IDLE_TIMEOUT", 2*time.Minute),
+			ShutdownDelay:   getEnvDuration("SERVER_SHUTDOWN_DELAY", 0),
+			ShutdownTimeout: getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			RequireIfMatch:  getEnvBool("SERVER_REQUIRE_IF_MATCH", false),
 		},
 		ExternalAPI: ExternalAPIConfig{
-			BaseURL: getEnvOrDefault("EXTERNAL_API_BASE_URL", ""),
-			InfoURL: getEnvOrDefault("EXTERNAL_API_INFO_PATH", ""),
+			BaseURL:          getEnvOrDefault("EXTERNAL_API_BASE_URL", ""),
+			InfoURL:          getEnvOrDefault("EXTERNAL_API_INFO_PATH", ""),
+			Timeout:          getEnvDuration("EXTERNAL_API_TIMEOUT", 5*time.Second),
+			MaxRetries:       getEnvInt("EXTERNAL_API_MAX_RETRIES", 3),
+			BackoffInitial:   getEnvDuration("EXTERNAL_API_BACKOFF_INITIAL", 200*time.Millisecond),
+			BackoffMax:       getEnvDuration("EXTERNAL_API_BACKOFF_MAX", 2*time.Second),
+			BreakerThreshold: getEnvInt("EXTERNAL_API_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvDuration("EXTERNAL_API_BREAKER_COOLDOWN", 30*time.Second),
+		},
+		Enrichment: EnrichmentConfig{
+			Providers:       getEnvList("ENRICHMENT_PROVIDERS", "catalog,api"),
+			CatalogPath:     getEnvOrDefault("ENRICHMENT_CATALOG_PATH", "enrichInfoSong.json"),
+			ReleaseDateFrom: getEnvList("ENRICHMENT_RELEASE_DATE_FROM", ""),
+			TextFrom:        getEnvList("ENRICHMENT_TEXT_FROM", ""),
+			LinkFrom:        getEnvList("ENRICHMENT_LINK_FROM", ""),
+			Async:           getEnvBool("ENRICHMENT_ASYNC", false),
+			Workers:         getEnvInt("ENRICHMENT_WORKERS", 4),
+			PollInterval:    getEnvDuration("ENRICHMENT_POLL_INTERVAL", time.Second),
+			MaxAttempts:     getEnvInt("ENRICHMENT_MAX_ATTEMPTS", 5),
+			RetryDelay:      getEnvDuration("ENRICHMENT_RETRY_DELAY", 10*time.Second),
+			JobStaleAfter:   getEnvDuration("ENRICHMENT_JOB_STALE_AFTER", 5*time.Minute),
+			CacheSize:       getEnvInt("ENRICHMENT_CACHE_SIZE", 0),
+			CacheTTL:        getEnvDuration("ENRICHMENT_CACHE_TTL", time.Hour),
+		},
+		Trash: TrashConfig{
+			Retention:     getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnvOrDefault("TRACING_EXPORTER", "none"),
+			ServiceName: getEnvOrDefault("TRACING_SERVICE_NAME", "music-library"),
+			Endpoint:    getEnvOrDefault("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			Insecure:    getEnvBool("TRACING_OTLP_INSECURE", true),
+			File:        getEnvOrDefault("TRACING_FILE", ""),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Log: LogConfig{
+			Level:  getEnvOrDefault("LOG_LEVEL", "info"),
+			Format: getEnvOrDefault("LOG_FORMAT", "json"),
+			Output: getEnvOrDefault("LOG_OUTPUT", ""),
 		},
 	}
 }
 
+// Validate reports settings that are missing or out of range, along with
+// environment values that could not be parsed.
+func (c Config) Validate() []error {
+	problems := make([]error, 0)
+	for _, value := range invalidValues {
+		problems = append(problems, errors.New(value))
+	}
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.DB.URL != "", "DATABASE_URL is not set")
+	check(validPort(c.Server.Port), "SERVER_PORT %q is not a valid port", c.Server.Port)
+	check(c.Server.MockServerPort == "" || validPort(c.Server.MockServerPort), "SERVER_MOCK_SERVER_PORT %q is not a valid port", c.Server.MockServerPort)
+
+	if slices.Contains(c.Enrichment.Providers, "api") {
+		base, err := url.Parse(c.ExternalAPI.BaseURL)
+		check(err == nil && (base.Scheme == "http" || base.Scheme == "https") && base.Host != "",
+			"EXTERNAL_API_BASE_URL %q is not an http or https URL", c.ExternalAPI.BaseURL)
+	}
+	check(c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.IdleTimeout >= 0, "SERVER_*_TIMEOUT must not be negative")
+	check(c.Server.ShutdownDelay >= 0, "SERVER_SHUTDOWN_DELAY must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT must be positive")
+	check(c.ExternalAPI.Timeout > 0, "EXTERNAL_API_TIMEOUT must be positive")
+	check(c.ExternalAPI.MaxRetries >= 0, "EXTERNAL_API_MAX_RETRIES must not be negative")
+	check(c.ExternalAPI.BreakerThreshold > 0, "EXTERNAL_API_BREAKER_THRESHOLD must be positive")
+
+	check(len(c.Enrichment.Providers) > 0, "ENRICHMENT_PROVIDERS is empty")
+	// No workers turns the background enrichment off.
+	check(c.Enrichment.Workers >= 0, "ENRICHMENT_WORKERS must not be negative")
+	check(c.Enrichment.Workers > 0 || !c.Enrichment.Async, "ENRICHMENT_WORKERS must be positive when ENRICHMENT_ASYNC is on")
+	check(c.Enrichment.Workers == 0 || c.Enrichment.PollInterval > 0, "ENRICHMENT_POLL_INTERVAL must be positive")
+	check(c.Enrichment.MaxAttempts > 0, "ENRICHMENT_MAX_ATTEMPTS must be positive")
+
+	check(c.Trash.Retention >= 0, "TRASH_RETENTION must not be negative")
+
+	check(slices.Contains([]string{"none", "otlp", "stdout"}, c.Tracing.Exporter), "TRACING_EXPORTER %q must be none, otlp or stdout", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+
+	check(slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(c.Log.Level)), "LOG_LEVEL %q must be debug, info, warn or error", c.Log.Level)
+	check(slices.Contains([]string{"json", "text"}, strings.ToLower(c.Log.Format)), "LOG_FORMAT %q must be json or text", c.Log.Format)
+
+	return problems
+}
+
+func validPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port < 65536
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -68,3 +246,69 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvList(key, defaultValue string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(getEnvOrDefault(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Error("invalid integer in environment, using default", slog.String("key", key), slog.Any("err", err))
+		invalidValues = append(invalidValues, fmt.Sprintf("%s=%q is not a valid integer", key, value))
+		return defaultValue
+	}
+	return number
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		logger.Error("invalid number in environment, using default", slog.String("key", key), slog.Any("err", err))
+		invalidValues = append(invalidValues, fmt.Sprintf("%s=%q is not a valid number", key, value))
+		return defaultValue
+	}
+	return number
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error("invalid duration in environment, using default", slog.String("key", key), slog.Any("err", err))
+		invalidValues = append(invalidValues, fmt.Sprintf("%s=%q is not a valid duration", key, value))
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Error("invalid boolean in environment, using default", slog.String("key", key), slog.Any("err", err))
+		invalidValues = append(invalidValues, fmt.Sprintf("%s=%q is not a valid boolean", key, value))
+		return defaultValue
+	}
+	return flag
+}
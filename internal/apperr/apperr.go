@@ -0,0 +1,155 @@
+// Package apperr defines the errors handlers report and renders them as
+// RFC 7807 problem details with a stable error code.
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Code identifies the kind of an error. Codes are part of the API: clients
+// may branch on them, so existing ones must not change.
+type Code string
+
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeValidation           Code = "validation_failed"
+	CodeNotFound             Code = "not_found"
+	CodeConflict             Code = "conflict"
+	CodeGroupNotEmpty        Code = "group_not_empty"
+	CodePreconditionFailed   Code = "precondition_failed"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeTooLarge             Code = "payload_too_large"
+	CodeUpstreamUnavailable  Code = "upstream_unavailable"
+	CodeUpstreamTimeout      Code = "upstream_timeout"
+	CodeInternal             Code = "internal_error"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:           http.StatusBadRequest,
+	CodeValidation:           http.StatusBadRequest,
+	CodeNotFound:             http.StatusNotFound,
+	CodeConflict:             http.StatusConflict,
+	CodeGroupNotEmpty:        http.StatusConflict,
+	CodePreconditionFailed:   http.StatusPreconditionFailed,
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+	CodeTooLarge:             http.StatusRequestEntityTooLarge,
+	CodeUpstreamUnavailable:  http.StatusBadGateway,
+	CodeUpstreamTimeout:      http.StatusGatewayTimeout,
+	CodeInternal:             http.StatusInternalServerError,
+}
+
+// FieldError describes one invalid field of a request body or one invalid
+// query parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error meant for the client. Detail and Extensions are shown
+// to the client, while Err, the underlying cause, is only logged.
+type Error struct {
+	Code       Code
+	Detail     string
+	Fields     []FieldError
+	Extensions map[string]any
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Detail, e.Err)
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// With adds an extension member to the problem details, such as the line
+// of an uploaded file where parsing stopped.
+func (e *Error) With(key string, value any) *Error {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// Status returns the HTTP status of the error.
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Detail: fmt.Sprintf(format, args...)}
+}
+
+func BadRequest(format string, args ...any) *Error {
+	return New(CodeBadRequest, format, args...)
+}
+
+func NotFound(format string, args ...any) *Error {
+	return New(CodeNotFound, format, args...)
+}
+
+func Conflict(format string, args ...any) *Error {
+	return New(CodeConflict, format, args...)
+}
+
+// PreconditionFailed reports an If-Match header that does not match the
+// current ETag of the resource.
+func PreconditionFailed(format string, args ...any) *Error {
+	return New(CodePreconditionFailed, format, args...)
+}
+
+// PreconditionRequired reports a missing If-Match header.
+func PreconditionRequired(format string, args ...any) *Error {
+	return New(CodePreconditionRequired, format, args...)
+}
+
+func TooLarge(format string, args ...any) *Error {
+	return New(CodeTooLarge, format, args...)
+}
+
+// Validation reports a request that is well-formed but has invalid fields.
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Detail: detail, Fields: fields}
+}
+
+// InvalidParam reports a single invalid query or path parameter.
+func InvalidParam(param, message string) *Error {
+	return Validation(fmt.Sprintf("invalid %s: %s", param, message), FieldError{Field: param, Message: message})
+}
+
+// Internal hides err from the client behind a generic message.
+func Internal(err error) *Error {
+	return &Error{Code: CodeInternal, Detail: "internal server error", Err: err}
+}
+
+// Upstream reports a failed call to the info API: 504 when it timed out and
+// 502 otherwise, including while its circuit breaker is open.
+func Upstream(err error) *Error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &Error{Code: CodeUpstreamTimeout, Detail: "the music info service did not respond in time", Err: err}
+	}
+	return &Error{Code: CodeUpstreamUnavailable, Detail: "the music info service is unavailable", Err: err}
+}
+
+// From returns err as an *Error, treating errors of any other type as
+// internal ones.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
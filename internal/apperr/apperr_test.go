@@ -0,0 +1,167 @@
+package apperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestUpstream(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   Code
+	}{
+		{"deadline exceeded", fmt.Errorf("get info: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, CodeUpstreamTimeout},
+		{"network timeout", fmt.Errorf("get info: %w", timeoutError{}), http.StatusGatewayTimeout, CodeUpstreamTimeout},
+		{"server error", errors.New("unexpected status 500"), http.StatusBadGateway, CodeUpstreamUnavailable},
+		{"canceled", context.Canceled, http.StatusBadGateway, CodeUpstreamUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Upstream(tt.err)
+			if got.Status() != tt.status || got.Code != tt.code {
+				t.Errorf("Upstream(%v) = %d %s, want %d %s", tt.err, got.Status(), got.Code, tt.status, tt.code)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("Upstream(%v) does not wrap the cause", tt.err)
+			}
+		})
+	}
+}
+
+// serve runs a router with Middleware and returns the decoded problem.
+func serve(t *testing.T, r *gin.Engine, target string) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	var problem Problem
+	if w.Body.Len() > 0 && w.Header().Get("Content-Type") == ContentType {
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("invalid problem %s: %v", w.Body, err)
+		}
+	}
+	return w, problem
+}
+
+func newRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware())
+	r.NoRoute(NoRoute)
+	return r
+}
+
+func TestMiddlewareRendersLastError(t *testing.T) {
+	r := newRouter()
+	r.GET("/errors", func(c *gin.Context) {
+		_ = c.Error(NotFound("first"))
+		Abort(c, Conflict("song %d changed", 1).With("version", 3))
+	})
+	r.GET("/internal", func(c *gin.Context) { Abort(c, errors.New("connection refused")) })
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "short and stout")
+		_ = c.Error(NotFound("ignored"))
+	})
+
+	w, problem := serve(t, r, "/errors")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("got status %d, want 409: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	want := Problem{Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "song 1 changed", Instance: "/errors", Code: CodeConflict}
+	if !reflect.DeepEqual(problem, want) {
+		t.Errorf("problem = %+v, want %+v", problem, want)
+	}
+	var members map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &members)
+	if members["version"] != 3.0 {
+		t.Errorf("extension version = %v, want 3: %s", members["version"], w.Body)
+	}
+
+	// The cause of an internal error is not shown.
+	w, problem = serve(t, r, "/internal")
+	if w.Code != http.StatusInternalServerError || problem.Code != CodeInternal || problem.Detail != "internal server error" {
+		t.Errorf("internal error = %d %+v", w.Code, problem)
+	}
+
+	// A response written by the handler is left alone.
+	w, _ = serve(t, r, "/written")
+	if w.Code != http.StatusTeapot || w.Body.String() != "short and stout" {
+		t.Errorf("written response = %d %s", w.Code, w.Body)
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	w, problem := serve(t, newRouter(), "/missing")
+	if w.Code != http.StatusNotFound || problem.Code != CodeNotFound || problem.Detail != "no route for GET /missing" {
+		t.Errorf("unknown route = %d %+v", w.Code, problem)
+	}
+}
+
+func TestInvalidBody(t *testing.T) {
+	type request struct {
+		Group string `json:"group" binding:"required"`
+		Song  string `json:"song" binding:"required,max=3"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		code   Code
+		fields []FieldError
+	}{
+		{
+			name: "invalid fields",
+			body: `{"song": "Uprising"}`,
+			code: CodeValidation,
+			fields: []FieldError{
+				{Field: "group", Message: "is required"},
+				{Field: "song", Message: "must be at most 3 characters long"},
+			},
+		},
+		{
+			name:   "wrong type",
+			body:   `{"group": "Muse", "song": "Up", "count": "many"}`,
+			code:   CodeValidation,
+			fields: []FieldError{{Field: "count", Message: "expected int"}},
+		},
+		{name: "broken JSON", body: `{"group": "Muse"`, code: CodeBadRequest},
+		{name: "syntax error", body: `{"group" "Muse"}`, code: CodeBadRequest},
+		{name: "empty", body: ``, code: CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body request
+			err := binding.JSON.BindBody([]byte(tt.body), &body)
+			if err == nil {
+				t.Fatal("BindBody succeeded")
+			}
+			got := InvalidBody(err)
+			if got.Code != tt.code || !reflect.DeepEqual(got.Fields, tt.fields) {
+				t.Errorf("InvalidBody() = %s %+v, want %s %+v", got.Code, got.Fields, tt.code, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package apperr
+
+import (
+	"effectiveMobileTask/internal/validation"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"io"
+)
+
+// InvalidBody turns an error from binding a JSON request body into a
+// validation error listing the offending fields.
+func InvalidBody(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, FieldError{Field: validation.Field(fieldErr), Message: validation.Message(fieldErr)})
+		}
+		return &Error{Code: CodeValidation, Detail: "request body has invalid fields", Fields: fields, Err: err}
+	case errors.As(err, &typeErr):
+		return &Error{
+			Code:   CodeValidation,
+			Detail: "request body has invalid fields",
+			Fields: []FieldError{{Field: typeErr.Field, Message: "expected " + typeErr.Type.String()}},
+			Err:    err,
+		}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return &Error{Code: CodeBadRequest, Detail: "request body is not valid JSON", Err: err}
+	case errors.Is(err, io.EOF):
+		return &Error{Code: CodeBadRequest, Detail: "request body is empty", Err: err}
+	}
+	return &Error{Code: CodeBadRequest, Detail: "invalid request body", Err: err}
+}
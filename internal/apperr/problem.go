@@ -0,0 +1,84 @@
+package apperr
+
+import (
+	"effectiveMobileTask/internal/requestlog"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Problem is the body of every error response, as described by RFC 7807.
+// Code and Errors are extension members.
+type Problem struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail,omitempty" example:"song 42 not found"`
+	Instance  string       `json:"instance,omitempty" example:"/songs/42"`
+	Code      Code         `json:"code" example:"not_found"`
+	RequestID string       `json:"request_id,omitempty" example:"3d8e1eb8d8127e84c7af8128309f68ef"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Extensions are further members specific to the error.
+	Extensions map[string]any `json:"-"`
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type members Problem
+	body, err := json.Marshal(members(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+	extensions, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	// Both are JSON objects: join them into one.
+	return append(append(body[:len(body)-1], ','), extensions[1:]...), nil
+}
+
+// Abort records err for Middleware to render and stops the handler chain.
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Middleware renders the last error recorded on the context as problem
+// details, unless the handler already wrote a response.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		Render(c, c.Errors.Last().Err)
+	}
+}
+
+// NoRoute answers requests to unknown routes.
+func NoRoute(c *gin.Context) {
+	Abort(c, NotFound("no route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
+
+// Render writes err as problem details. The cause of the error is left for
+// the handler to log; the client only sees the detail.
+func Render(c *gin.Context, err error) {
+	appErr := From(err)
+	status := appErr.Status()
+
+	c.Header("Content-Type", ContentType)
+	c.JSON(status, Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     appErr.Detail,
+		Instance:   c.Request.URL.Path,
+		Code:       appErr.Code,
+		RequestID:  requestlog.ID(c.Request.Context()),
+		Errors:     appErr.Fields,
+		Extensions: appErr.Extensions,
+	})
+}
@@ -0,0 +1,82 @@
+// Package audit tells the repositories who makes a change, so that they can
+// record it in the revision history.
+package audit
+
+import (
+	"context"
+	"effectiveMobileTask/lib/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+)
+
+// Header names the actor of a request. The service has no authentication,
+// so the value is taken as given; it only has to be printable.
+const Header = "X-Actor"
+
+const (
+	// Anonymous is the actor of requests without the header.
+	Anonymous = "anonymous"
+	// System is the actor of changes made outside of requests, such as by
+	// background workers.
+	System = "system"
+)
+
+// maxActorLength bounds the actor names accepted from clients.
+const maxActorLength = 128
+
+type actorKey struct{}
+
+type revertKey struct{}
+
+// WithActor returns a copy of ctx in which changes are made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor stored in ctx, or System.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok {
+		return actor
+	}
+	return System
+}
+
+// WithRevert returns a copy of ctx in which a song update is recorded as a
+// revert to the given revision.
+func WithRevert(ctx context.Context, revisionID uint) context.Context {
+	return context.WithValue(ctx, revertKey{}, revisionID)
+}
+
+// RevertOf returns the revision stored by WithRevert, or zero.
+func RevertOf(ctx context.Context) uint {
+	id, _ := ctx.Value(revertKey{}).(uint)
+	return id
+}
+
+// Middleware stores the actor of the request in its context and adds it to
+// the log attributes. It must run after requestlog.Middleware.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := c.GetHeader(Header)
+		if !validActor(actor) {
+			actor = Anonymous
+		}
+		ctx := logger.WithAttrs(WithActor(c.Request.Context(), actor), slog.String("actor", actor))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validActor accepts printable ASCII, so that actors cannot inject anything
+// odd into the logs.
+func validActor(actor string) bool {
+	if actor == "" || len(actor) > maxActorLength {
+		return false
+	}
+	for i := 0; i < len(actor); i++ {
+		if actor[i] < ' ' || actor[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
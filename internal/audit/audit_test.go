@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestMiddlewareActor(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "given", header: "alice", want: "alice"},
+		{name: "printable ASCII", header: "ci-bot <ops@example.com>", want: "ci-bot <ops@example.com>"},
+		{name: "missing", header: "", want: Anonymous},
+		{name: "control character", header: "alice\x1b[31m", want: Anonymous},
+		{name: "non-ASCII", header: "алиса", want: Anonymous},
+		{name: "too long", header: strings.Repeat("a", maxActorLength+1), want: Anonymous},
+		{name: "longest", header: strings.Repeat("a", maxActorLength), want: strings.Repeat("a", maxActorLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := gin.New()
+			r.Use(Middleware())
+			r.GET("/", func(c *gin.Context) { got = Actor(c.Request.Context()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("actor = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActorOutsideRequests(t *testing.T) {
+	if got := Actor(context.Background()); got != System {
+		t.Errorf("actor without WithActor = %q, want %q", got, System)
+	}
+	if got := Actor(WithActor(context.Background(), "alice")); got != "alice" {
+		t.Errorf("actor = %q, want alice", got)
+	}
+}
+
+func TestRevertOf(t *testing.T) {
+	if got := RevertOf(context.Background()); got != 0 {
+		t.Errorf("RevertOf without WithRevert = %d, want 0", got)
+	}
+	if got := RevertOf(WithRevert(context.Background(), 7)); got != 7 {
+		t.Errorf("RevertOf = %d, want 7", got)
+	}
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"time"
+)
+
+type ExportController struct {
+	exporter *songio.Exporter
+}
+
+func NewExportController(exporter *songio.Exporter) *ExportController {
+	return &ExportController{exporter: exporter}
+}
+
+// ExportSongs godoc
+// @Summary Export the song catalog
+// @Description Download all songs matching the filters as a file, with the same columns import accepts. Filters and sort work as in GET /songs, page and limit are ignored.
+// @Description The file is streamed while songs are read from the database in chunks.
+// @Tags Import
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param format query string false "File format" Enums(csv, json, ndjson, xlsx) default(csv)
+// @Param group query string false "Filter by group name"
+// @Param group_id query int false "Filter by group ID"
+// @Param song query string false "Filter by song name"
+// @Param match query string false "Match group and song names exactly or as substrings" Enums(fuzzy, exact) default(fuzzy)
+// @Param release_date query string false "Filter by release date (DD.MM.YYYY or YYYY-MM-DD)"
+// @Param release_from query string false "Released on or after (DD.MM.YYYY or YYYY-MM-DD)"
+// @Param release_to query string false "Released on or before (DD.MM.YYYY or YYYY-MM-DD)"
+// @Param year query int false "Released in the year"
+// @Param created_from query string false "Added at or after (RFC 3339)"
+// @Param created_to query string false "Added at or before (RFC 3339)"
+// @Param updated_from query string false "Updated at or after (RFC 3339)"
+// @Param updated_to query string false "Updated at or before (RFC 3339)"
+// @Param text query string false "Filter by song text"
+// @Param link query string false "Filter by link"
+// @Param sort query string false "Comma separated sort fields, - for descending: id, title, group, release_date, created_at, updated_at"
+// @Success 200 {file} file "Song catalog"
+// @Failure 400 {object} apperr.Problem "Invalid format or filter"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /export [get]
+func (ec *ExportController) ExportSongs(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	format, ok := songio.ParseFormat(c.DefaultQuery("format", string(songio.FormatCSV)))
+	if !ok {
+		apperr.Abort(c, apperr.InvalidParam("format", "expected csv, json, ndjson or xlsx"))
+		return
+	}
+
+	filter, paramErr := parseSongFilter(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+
+	filename := fmt.Sprintf("songs-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	written, err := ec.export(c, format, filter)
+	if err != nil {
+		log.Error("failed to export songs", slog.String("format", string(format)), slog.Any("error", err))
+		// Once the file has started, the status is sent and the download
+		// can only be cut short.
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		c.Header("Content-Disposition", "")
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("songs exported", slog.String("format", string(format)), slog.Int("songs", written))
+}
+
+func (ec *ExportController) export(c *gin.Context, format songio.Format, filter repository.SongFilter) (int, error) {
+	writer, err := songio.NewRecordWriter(format, c.Writer)
+	if err != nil {
+		return 0, err
+	}
+	written, err := ec.exporter.Export(c.Request.Context(), filter, writer)
+	if err != nil {
+		return written, errors.Join(err, writer.Abort())
+	}
+	return written, writer.Close()
+}
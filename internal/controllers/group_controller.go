@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type GroupController struct {
+	groups repository.GroupRepository
+}
+
+func NewGroupController(groups repository.GroupRepository) *GroupController {
+	return &GroupController{groups: groups}
+}
+
+type groupRequest struct {
+	Name string `json:"name" binding:"required,notblank,max=255" minLength:"1" example:"Muse"`
+}
+
+// groupPage is one page of GET /groups. Total counts every group matching
+// the filter.
+type groupPage struct {
+	Items []models.Group `json:"items"`
+	Total int64          `json:"total" example:"42"`
+	Page  int            `json:"page" example:"1"`
+	Limit int            `json:"limit" example:"10"`
+}
+
+type mergeGroupsRequest struct {
+	SourceID uint `json:"source_id" binding:"required" minimum:"1" example:"2"`
+}
+
+// ListGroups godoc
+// @Summary List groups with optional filtering
+// @Description Retrieve a list of groups filtered by name with pagination
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by Group Name"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10) minimum(1) maximum(100)
+// @Success 200 {object} groupPage "Groups retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid page or limit"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups [get]
+func (gc *GroupController) ListGroups(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	pageNumber, limitNumber, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+
+	filter := repository.GroupFilter{
+		Name:   c.Query("name"),
+		Offset: (pageNumber - 1) * limitNumber,
+		Limit:  limitNumber,
+	}
+	groups, err := gc.groups.List(ctx, filter)
+	if err != nil {
+		log.Error("failed to query groups", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+	total, err := gc.groups.Count(ctx, filter)
+	if err != nil {
+		log.Error("failed to count groups", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("groups retrieved successfully", slog.Int("count", len(groups)))
+	c.JSON(http.StatusOK, groupPage{
+		Items: groups,
+		Total: total,
+		Page:  pageNumber,
+		Limit: limitNumber,
+	})
+}
+
+// GetGroup godoc
+// @Summary Get a group with its songs
+// @Description Retrieve a group by ID together with all of its songs
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} models.Group "Group retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid group ID format"
+// @Failure 404 {object} apperr.Problem "Group not found"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups/{id} [get]
+func (gc *GroupController) GetGroup(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	group, err := gc.groups.GetWithSongs(c.Request.Context(), id)
+	if err != nil {
+		respondGroupError(c, "failed to fetch group", id, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// CreateGroup godoc
+// @Summary Create a group
+// @Description Create a new group with a unique name
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group body groupRequest true "Group"
+// @Success 201 {object} models.Group "Group created successfully"
+// @Failure 400 {object} apperr.Problem "Invalid request body"
+// @Failure 409 {object} apperr.Problem "Group with this name already exists"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups [post]
+func (gc *GroupController) CreateGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	name, ok := bindGroupName(c)
+	if !ok {
+		return
+	}
+
+	group := models.Group{Name: name}
+	if err := gc.groups.Create(ctx, &group); err != nil {
+		respondGroupError(c, "failed to create group", 0, err)
+		return
+	}
+
+	log.Info("group created successfully", slog.Any("id", group.ID))
+	c.JSON(http.StatusCreated, group)
+}
+
+// RenameGroup godoc
+// @Summary Rename a group
+// @Description Change the name of a group; the new name must not be used by another group.
+// @Description The versions, and so the ETags, of the songs of the group change too.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param group body groupRequest true "Group"
+// @Success 200 {object} models.Group "Group renamed successfully"
+// @Failure 400 {object} apperr.Problem "Invalid group ID or request body"
+// @Failure 404 {object} apperr.Problem "Group not found"
+// @Failure 409 {object} apperr.Problem "Group with this name already exists"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups/{id} [patch]
+func (gc *GroupController) RenameGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	name, ok := bindGroupName(c)
+	if !ok {
+		return
+	}
+
+	if err := gc.groups.Rename(ctx, id, name); err != nil {
+		respondGroupError(c, "failed to rename group", id, err)
+		return
+	}
+
+	group, err := gc.groups.GetByID(ctx, id)
+	if err != nil {
+		respondGroupError(c, "failed to fetch group", id, err)
+		return
+	}
+
+	log.Info("group renamed successfully", slog.Any("id", id), slog.String("name", group.Name))
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup godoc
+// @Summary Delete a group
+// @Description Delete a group. By default a group with songs is not deleted (songs=restrict);
+// @Description songs=cascade deletes its songs as well, songs=reassign moves them to target_id first.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param songs query string false "What to do with the group's songs" Enums(restrict, cascade, reassign) default(restrict)
+// @Param target_id query int false "Group receiving the songs when songs=reassign"
+// @Success 200 {object} map[string]string "Group deleted successfully"
+// @Failure 400 {object} apperr.Problem "Invalid parameters"
+// @Failure 404 {object} apperr.Problem "Group not found"
+// @Failure 409 {object} apperr.Problem "Group still has songs"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups/{id} [delete]
+func (gc *GroupController) DeleteGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	policy := repository.GroupDeletePolicy(c.DefaultQuery("songs", string(repository.GroupDeleteRestrict)))
+	var targetID uint
+
+	switch policy {
+	case repository.GroupDeleteRestrict, repository.GroupDeleteCascade:
+	case repository.GroupDeleteReassign:
+		target, err := strconv.Atoi(c.Query("target_id"))
+		if err != nil || target < 1 || uint(target) == id {
+			apperr.Abort(c, apperr.InvalidParam("target_id", "must be the ID of another group when songs=reassign"))
+			return
+		}
+		targetID = uint(target)
+
+		if _, err := gc.groups.GetByID(ctx, targetID); err != nil {
+			respondGroupError(c, "failed to fetch target group", targetID, err)
+			return
+		}
+	default:
+		apperr.Abort(c, apperr.InvalidParam("songs", "expected restrict, cascade or reassign"))
+		return
+	}
+
+	if err := gc.groups.Delete(ctx, id, policy, targetID); err != nil {
+		respondGroupError(c, "failed to delete group", id, err)
+		return
+	}
+
+	log.Info("group deleted successfully", slog.Any("id", id), slog.String("songs", string(policy)))
+	c.JSON(http.StatusOK, gin.H{"message": "group deleted successfully"})
+}
+
+// MergeGroups godoc
+// @Summary Merge a duplicate group into this one
+// @Description Move all songs of the source group to the group from the path and delete the source group
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Target Group ID"
+// @Param request body mergeGroupsRequest true "Source group"
+// @Success 200 {object} models.Group "Groups merged successfully"
+// @Failure 400 {object} apperr.Problem "Invalid parameters"
+// @Failure 404 {object} apperr.Problem "Group not found"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /groups/{id}/merge [post]
+func (gc *GroupController) MergeGroups(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request mergeGroupsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("invalid merge request", slog.Any("error", err))
+		apperr.Abort(c, apperr.InvalidBody(err))
+		return
+	}
+	if request.SourceID == id {
+		apperr.Abort(c, apperr.Validation("invalid merge request",
+			apperr.FieldError{Field: "source_id", Message: "must be the ID of another group"}))
+		return
+	}
+
+	if _, err := gc.groups.GetByID(ctx, id); err != nil {
+		respondGroupError(c, "failed to fetch target group", id, err)
+		return
+	}
+
+	if err := gc.groups.Merge(ctx, request.SourceID, id); err != nil {
+		respondGroupError(c, "failed to merge groups", request.SourceID, err)
+		return
+	}
+
+	group, err := gc.groups.GetWithSongs(ctx, id)
+	if err != nil {
+		respondGroupError(c, "failed to fetch group", id, err)
+		return
+	}
+
+	log.Info("groups merged successfully", slog.Any("source_id", request.SourceID), slog.Any("target_id", id))
+	c.JSON(http.StatusOK, group)
+}
+
+func groupIDParam(c *gin.Context) (uint, bool) {
+	log := logger.FromContext(c.Request.Context())
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		log.Error("invalid group ID format", slog.Any("id", c.Param("id")))
+		apperr.Abort(c, apperr.InvalidParam("id", "expected a positive integer"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func bindGroupName(c *gin.Context) (string, bool) {
+	log := logger.FromContext(c.Request.Context())
+
+	var request groupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("invalid group request body", slog.Any("error", err))
+		apperr.Abort(c, apperr.InvalidBody(err))
+		return "", false
+	}
+
+	return request.Name, true
+}
+
+func respondGroupError(c *gin.Context, message string, id uint, err error) {
+	log := logger.FromContext(c.Request.Context())
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		apperr.Abort(c, apperr.NotFound("group not found"))
+	case errors.Is(err, repository.ErrConflict):
+		apperr.Abort(c, apperr.Conflict("group with this name already exists"))
+	case errors.Is(err, repository.ErrGroupNotEmpty):
+		apperr.Abort(c, apperr.New(apperr.CodeGroupNotEmpty, "group still has songs, use songs=cascade or songs=reassign"))
+	default:
+		log.Error(message, slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+	}
+}
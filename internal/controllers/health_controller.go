@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/health"
+	"effectiveMobileTask/internal/version"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HealthController struct {
+	checker *health.Checker
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{checker: checker}
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Answers as long as the process is up; dependencies are not checked.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string "Process is up"
+// @Router /healthz [get]
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Checks the database connection, pending migrations and the circuit breaker of the enrichment API.
+// @Description Fails while a critical component is down and during graceful shutdown. An open circuit only marks the enrichment API as degraded.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Ready to take traffic"
+// @Failure 503 {object} health.Report "Not ready, components tell why"
+// @Router /readyz [get]
+func (hc *HealthController) Readyz(c *gin.Context) {
+	report := hc.checker.Check(c.Request.Context())
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Version godoc
+// @Summary Build information
+// @Tags Health
+// @Produce json
+// @Success 200 {object} version.Info "Build information"
+// @Router /version [get]
+func (hc *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
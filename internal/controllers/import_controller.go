@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// maxImportSize limits the size of an import request.
+const maxImportSize = 64 << 20
+
+type ImportController struct {
+	importer *songio.Importer
+}
+
+func NewImportController(importer *songio.Importer) *ImportController {
+	return &ImportController{importer: importer}
+}
+
+// ImportSongs godoc
+// @Summary Import songs from a CSV, JSON or NDJSON file
+// @Description Add many songs at once. Each record has group and song and optionally release_date (DD.MM.YYYY or YYYY-MM-DD), text and link;
+// @Description CSV files need a header row. The file is read as a stream, sent as the request body or as the "file" field of a multipart form.
+// @Description Invalid records and songs that already exist are skipped and listed in the report.
+// @Tags Import
+// @Accept plain
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param file body string true "Songs file"
+// @Param format query string false "File format, taken from the content type or file name when omitted" Enums(csv, json, ndjson)
+// @Param dry_run query bool false "Only validate and report what would be imported"
+// @Param enrich query bool false "Look up release date, text and link for records missing them"
+// @Success 200 {object} songio.ImportReport "File imported"
+// @Failure 400 {object} apperr.Problem "Unknown format or unreadable file; the report tells how far the import got"
+// @Failure 413 {object} apperr.Problem "File too large"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /import [post]
+func (ic *ImportController) ImportSongs(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	var opts songio.ImportOptions
+	var err error
+	if opts.DryRun, err = boolParam(c, "dry_run"); err != nil {
+		apperr.Abort(c, apperr.InvalidParam("dry_run", "expected true or false"))
+		return
+	}
+	if opts.Enrich, err = boolParam(c, "enrich"); err != nil {
+		apperr.Abort(c, apperr.InvalidParam("enrich", "expected true or false"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	body, format, err := importBody(c)
+	if err != nil {
+		apperr.Abort(c, err)
+		return
+	}
+
+	reader, err := songio.NewRecordReader(format, body)
+	if err != nil {
+		apperr.Abort(c, fileError(err))
+		return
+	}
+
+	report, err := ic.importer.Import(ctx, reader, opts)
+	if err != nil {
+		problem := apperr.Internal(err)
+		if errors.Is(err, songio.ErrUnreadable) {
+			problem = fileError(err)
+		} else {
+			log.Error("failed to import songs", slog.Any("error", err))
+		}
+		apperr.Abort(c, problem.With("report", report))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// importBody finds the file to import and its format. A multipart form is
+// read part by part, so the file is not buffered either way.
+func importBody(c *gin.Context) (io.Reader, songio.Format, error) {
+	format, explicit := songio.ParseFormat(c.Query("format"))
+	if c.Query("format") != "" && !explicit {
+		return nil, "", apperr.InvalidParam("format", "expected csv, json or ndjson")
+	}
+
+	if c.ContentType() != "multipart/form-data" {
+		if !explicit {
+			if format, explicit = songio.ParseFormat(c.ContentType()); !explicit {
+				return nil, "", apperr.InvalidParam("format", "cannot be told from the file, pass csv, json or ndjson")
+			}
+		}
+		return c.Request.Body, format, nil
+	}
+
+	multipart, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, "", fileError(err)
+	}
+	for {
+		part, err := multipart.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, "", apperr.BadRequest(`multipart form has no "file" field`)
+			}
+			return nil, "", fileError(err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		if !explicit {
+			if format, explicit = songio.ParseFormat(part.FileName()); !explicit {
+				if format, explicit = songio.ParseFormat(part.Header.Get("Content-Type")); !explicit {
+					return nil, "", apperr.InvalidParam("format", "cannot be told from the file, pass csv, json or ndjson")
+				}
+			}
+		}
+		return part, format, nil
+	}
+}
+
+func boolParam(c *gin.Context, name string) (bool, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// fileError reports an uploaded file that could not be read.
+func fileError(err error) *apperr.Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperr.TooLarge("file is larger than %d bytes", tooLarge.Limit)
+	}
+	return &apperr.Error{Code: apperr.CodeBadRequest, Detail: err.Error(), Err: err}
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type JobController struct {
+	jobs repository.JobRepository
+}
+
+func NewJobController(jobs repository.JobRepository) *JobController {
+	return &JobController{jobs: jobs}
+}
+
+// GetJob godoc
+// @Summary Get enrichment job status
+// @Description Retrieve the state of an asynchronous enrichment job created by POST /info?async=true
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} models.EnrichmentJob "Job retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid job ID format"
+// @Failure 404 {object} apperr.Problem "Job not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /jobs/{id} [get]
+func (jc *JobController) GetJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Error("invalid job ID format", slog.Any("id", c.Param("id")))
+		apperr.Abort(c, apperr.InvalidParam("id", "expected a positive integer"))
+		return
+	}
+
+	job, err := jc.jobs.GetByID(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("job not found"))
+			return
+		}
+		log.Error("failed to fetch job", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
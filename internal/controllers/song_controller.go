@@ -1,90 +1,160 @@
 package controllers
 
 import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/enrichment"
 	"effectiveMobileTask/internal/models"
-	"effectiveMobileTask/internal/storage/database"
+	"effectiveMobileTask/internal/storage/repository"
 	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-type SongEnriched struct {
-	ReleaseDate string `json:"release_date"`
-	Group       string `json:"group"`
-	Song        string `json:"song"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+type SongControllerConfig struct {
+	// AsyncEnrichment makes POST /info enqueue enrichment jobs unless the
+	// request asks otherwise with ?async=false.
+	AsyncEnrichment bool
+	// TrashRetention is the default age of trash items removed by
+	// DELETE /songs/trash.
+	TrashRetention time.Duration
+	// RequireIfMatch makes PATCH and DELETE /songs/{id}, PUT
+	// /songs/{id}/lyrics and reverts fail with 428 Precondition Required
+	// unless they send If-Match.
+	RequireIfMatch bool
+}
+
+type SongController struct {
+	songs     repository.SongRepository
+	groups    repository.GroupRepository
+	revisions repository.RevisionRepository
+	enricher  enrichment.SongEnricher
+	cfg       SongControllerConfig
+}
+
+func NewSongController(songs repository.SongRepository, groups repository.GroupRepository, revisions repository.RevisionRepository, enricher enrichment.SongEnricher, cfg SongControllerConfig) *SongController {
+	return &SongController{
+		songs:     songs,
+		groups:    groups,
+		revisions: revisions,
+		enricher:  enricher,
+		cfg:       cfg,
+	}
 }
 
 type songRequest struct {
-	Group string `json:"group" example:"Muse"` // Пример значения
-	Song  string `json:"song" example:"Supermassive Black Hole"`
+	Group string `json:"group" binding:"required,notblank,max=255" minLength:"1" example:"Muse"` // Пример значения
+	Song  string `json:"song" binding:"required,notblank,max=255" minLength:"1" example:"Supermassive Black Hole"`
+	// Language is the text search configuration used to index the lyrics.
+	Language string `json:"language,omitempty" binding:"omitempty,language" example:"english"`
+}
+
+type enrichmentAccepted struct {
+	SongID           uint   `json:"song_id" example:"1"`
+	JobID            uint   `json:"job_id" example:"1"`
+	EnrichmentStatus string `json:"enrichment_status" example:"pending"`
 }
 
 // AddSongInfo godoc
 // @Summary Add song information
-// @Description Add new song information from group and title
+// @Description Add new song information from group and title. With async=true the song is created
+// @Description immediately with enrichment_status "pending" and an enrichment job is queued; poll /jobs/{id}.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param request body songRequest true "Request Body"
+// @Param async query bool false "Enrich the song in the background"
 // @Success 200 {object} models.SongDetail "Song details successfully added"
-// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
-// @Failure 404 {object} map[string]string "Song not found"
-// @Failure 500 {object} map[string]string "Internal server error - database or API error"
+// @Success 202 {object} enrichmentAccepted "Song created, enrichment job queued"
+// @Failure 400 {object} apperr.Problem "Bad request - missing or invalid parameters"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Failure 502 {object} apperr.Problem "The music info service failed or its circuit breaker is open"
+// @Failure 504 {object} apperr.Problem "The music info service did not respond in time"
 // @Router /info [post]
-func AddSongInfo(c *gin.Context) {
+func (sc *SongController) AddSongInfo(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
 	var requestBody songRequest
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		logger.Error("invalid request body", slog.Any("error", err))
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request body"})
+		log.Error("invalid request body", slog.Any("error", err))
+		apperr.Abort(c, apperr.InvalidBody(err))
 		return
 	}
 
 	groupName := requestBody.Group
 	songTitle := requestBody.Song
 
-	db := database.DbConnect()
-
-	var Group models.Group
-	if err := db.Where("name = ?", groupName).FirstOrCreate(&Group, models.Group{Name: groupName}).Error; err != nil {
-		logger.Error("failed to find or create artist", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server errors"})
+	group, err := sc.groups.FirstOrCreate(ctx, groupName)
+	if err != nil {
+		log.Error("failed to find or create artist", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
 		return
 	}
+	// The repository trims the name; the song is stored under that one.
+	groupName = group.Name
+
+	song, err := sc.songs.GetByGroupAndTitle(ctx, group.ID, songTitle)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Error("failed to find song", slog.Any("error", err))
+			apperr.Abort(c, apperr.Internal(err))
+			return
+		}
+
+		log.Info("song not found", slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
+
+		async := sc.cfg.AsyncEnrichment
+		if value, ok := c.GetQuery("async"); ok {
+			async, err = strconv.ParseBool(value)
+			if err != nil {
+				apperr.Abort(c, apperr.InvalidParam("async", "expected true or false"))
+				return
+			}
+		}
 
-	var song models.Song
-	if err := db.Where("group_id = ? AND song = ?", Group.ID, songTitle).First(&song).Error; err != nil {
-		logger.Info("song not found", slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
-		songDetail, boolReturn := GetSongDetailAPI(groupName, songTitle, c)
-		if boolReturn {
+		if async {
+			sc.addSongAsync(c, group, songTitle, requestBody.Language)
 			return
 		}
 
-		releaseDate, err := time.Parse("02.01.2006", songDetail.ReleaseDate)
+		songDetail, err := sc.enricher.Enrich(ctx, groupName, songTitle)
 		if err != nil {
-			logger.Error("failed to parse release date", slog.Any("error", err))
-			releaseDate = time.Now()
+			if errors.Is(err, enrichment.ErrNotFound) {
+				log.Info("song details not found", slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
+				apperr.Abort(c, apperr.NotFound("song not found"))
+				return
+			}
+			log.Error("failed to get song detail", slog.Any("error", err))
+			apperr.Abort(c, apperr.Upstream(err))
+			return
 		}
 
 		newSong := models.Song{
-			GroupId:     Group.ID,
-			Title:       songTitle,
-			ReleaseDate: releaseDate,
-			Text:        songDetail.Text,
-			Link:        songDetail.Link,
+			GroupId:          group.ID,
+			Title:            songTitle,
+			Language:         requestBody.Language,
+			EnrichmentStatus: models.EnrichmentStatusEnriched,
+		}
+
+		if err := enrichment.Apply(&newSong, songDetail); err != nil {
+			log.Error("failed to parse release date", slog.Any("error", err))
+			newSong.ReleaseDate = time.Now()
 		}
 
-		if err := db.Create(&newSong).Error; err != nil {
-			logger.Error("failed to add new song", slog.Any("error", err), slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		if err := sc.songs.Create(ctx, &newSong); err != nil {
+			log.Error("failed to add new song", slog.Any("error", err), slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
+			apperr.Abort(c, apperr.Internal(err))
 			return
 		}
-		logger.Info("added new song", slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
-		song = newSong
+		log.Info("added new song", slog.Any("params", map[string]string{"group": groupName, "song": songTitle}))
+		song = &newSong
 	}
 
 	releaseDateStr := song.ReleaseDate.Format("02.01.2006")
@@ -97,6 +167,46 @@ func AddSongInfo(c *gin.Context) {
 		Link:        song.Link,
 	}
 
-	SongEnrichFromJSON(&songDetail, groupName, songTitle)
 	c.JSON(http.StatusOK, songDetail)
 }
+
+func (sc *SongController) addSongAsync(c *gin.Context, group *models.Group, songTitle, language string) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	song := models.Song{
+		GroupId:          group.ID,
+		Title:            songTitle,
+		Language:         language,
+		EnrichmentStatus: models.EnrichmentStatusPending,
+	}
+	job := models.EnrichmentJob{
+		Group: group.Name,
+		Song:  songTitle,
+	}
+	if err := sc.songs.CreateWithJob(ctx, &song, &job); err != nil {
+		log.Error("failed to add new song with an enrichment job", slog.Any("error", err), slog.Any("params", map[string]string{"group": group.Name, "song": songTitle}))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("added new song, enrichment queued", slog.Any("song_id", song.ID), slog.Any("job_id", job.ID))
+	c.Header("Location", fmt.Sprintf("/jobs/%d", job.ID))
+	c.JSON(http.StatusAccepted, enrichmentAccepted{
+		SongID:           song.ID,
+		JobID:            job.ID,
+		EnrichmentStatus: song.EnrichmentStatus,
+	})
+}
+
+func songIDParam(c *gin.Context) (uint, bool) {
+	log := logger.FromContext(c.Request.Context())
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		log.Error("invalid song ID format", slog.Any("id", c.Param("id")))
+		apperr.Abort(c, apperr.InvalidParam("id", "expected a positive integer"))
+		return 0, false
+	}
+	return uint(id), true
+}
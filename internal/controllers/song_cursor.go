@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	errInvalidCursor      = errors.New("invalid cursor")
+	errCursorSortMismatch = errors.New("cursor was issued for a different sort order")
+)
+
+// songPage is returned by GET /songs in cursor mode.
+type songPage struct {
+	Items []models.Song `json:"items"`
+	// NextCursor and PrevCursor are passed back as ?cursor= to get the
+	// following and the preceding page. They are omitted at either end.
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzb3J0IjoiaWQiLCJpZCI6Mn0"`
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJzb3J0IjoiaWQiLCJpZCI6MSwiYmVmb3JlIjp0cnVlfQ"`
+	// Total is only filled with ?total=true.
+	Total *int64 `json:"total,omitempty" example:"42"`
+}
+
+// cursorToken is what an opaque cursor holds. Clients must not rely on it.
+type cursorToken struct {
+	Sort   string   `json:"sort"`
+	Values []string `json:"values,omitempty"`
+	ID     uint     `json:"id"`
+	Before bool     `json:"before,omitempty"`
+}
+
+func encodeCursor(cursor repository.SongCursor, sort []repository.SongSort) string {
+	data, _ := json.Marshal(cursorToken{
+		Sort:   repository.FormatSongSort(sort),
+		Values: cursor.Values,
+		ID:     cursor.ID,
+		Before: cursor.Before,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns nil for an empty token, which stands for the first
+// page. A cursor only fits the sort order it was issued for.
+func decodeCursor(token string, sort []repository.SongSort) (*repository.SongCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var decoded cursorToken
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == 0 {
+		return nil, errInvalidCursor
+	}
+	if decoded.Sort != repository.FormatSongSort(sort) {
+		return nil, errCursorSortMismatch
+	}
+	return &repository.SongCursor{Values: decoded.Values, ID: decoded.ID, Before: decoded.Before}, nil
+}
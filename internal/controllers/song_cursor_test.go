@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/storage/repository"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	sort := []repository.SongSort{{Field: repository.SortByTitle, Desc: true}, {Field: repository.SortByID}}
+	cursors := []repository.SongCursor{
+		{Values: []string{"Uprising"}, ID: 7},
+		{Values: []string{"Uprising, \"live\""}, ID: 8, Before: true},
+	}
+	for _, cursor := range cursors {
+		decoded, err := decodeCursor(encodeCursor(cursor, sort), sort)
+		if err != nil {
+			t.Fatalf("decodeCursor: %v", err)
+		}
+		if !reflect.DeepEqual(*decoded, cursor) {
+			t.Errorf("cursor %+v came back as %+v", cursor, *decoded)
+		}
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	sort := repository.DefaultSongSort
+	valid := encodeCursor(repository.SongCursor{ID: 3}, sort)
+	byTitle := []repository.SongSort{{Field: repository.SortByTitle}, {Field: repository.SortByID}}
+
+	tests := []struct {
+		name    string
+		token   string
+		sort    []repository.SongSort
+		wantNil bool
+		wantErr error
+	}{
+		{name: "first page", token: "", sort: sort, wantNil: true},
+		{name: "valid", token: valid, sort: sort},
+		{name: "other sort order", token: valid, sort: byTitle, wantNil: true, wantErr: errCursorSortMismatch},
+		{name: "not base64", token: "!!!", sort: sort, wantNil: true, wantErr: errInvalidCursor},
+		{name: "not JSON", token: "bm90IGpzb24", sort: sort, wantNil: true, wantErr: errInvalidCursor},
+		{name: "no id", token: "eyJzb3J0IjoiaWQifQ", sort: sort, wantNil: true, wantErr: errInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCursor(tt.token, tt.sort)
+			if err != tt.wantErr {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if (cursor == nil) != tt.wantNil {
+				t.Errorf("cursor = %+v", cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// songETag returns the entity tag of the song. It is derived from the
+// version alone, so every representation of the song shares it and it
+// changes whenever the song does.
+func songETag(song *models.Song) string {
+	return `"` + strconv.FormatUint(uint64(song.Version), 10) + `"`
+}
+
+// etagListed reports whether an If-Match or If-None-Match header value is
+// "*" or lists etag. Weak tags only match when weak is set, as If-None-Match
+// compares weakly and If-Match strongly.
+func etagListed(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets the ETag header for the song. When If-None-Match lists
+// the tag, it responds with 304 Not Modified itself and returns true.
+func notModified(c *gin.Context, song *models.Song) bool {
+	etag := songETag(song)
+	c.Header("ETag", etag)
+	if header := c.GetHeader("If-None-Match"); header != "" && etagListed(header, etag, true) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// checkIfMatch makes sure a request changing the song sent the current ETag
+// in If-Match, or no If-Match at all when the header is optional. It responds
+// with an error itself and returns false otherwise.
+func (sc *SongController) checkIfMatch(c *gin.Context, song *models.Song) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		if sc.cfg.RequireIfMatch {
+			apperr.Abort(c, apperr.PreconditionRequired("If-Match header is required, send the ETag of the song"))
+			return false
+		}
+		return true
+	}
+	if !etagListed(header, songETag(song), false) {
+		apperr.Abort(c, apperr.PreconditionFailed("the song has changed since it was read"))
+		return false
+	}
+	return true
+}
+
+// versionMismatch reports a song changed by another request between reading
+// and writing it: a failed precondition when the client sent If-Match and a
+// conflict worth retrying otherwise.
+func versionMismatch(c *gin.Context) *apperr.Error {
+	if c.GetHeader("If-Match") != "" {
+		return apperr.PreconditionFailed("the song has changed since it was read")
+	}
+	return apperr.Conflict("the song was changed by another request, try again")
+}
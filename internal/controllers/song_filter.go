@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// parseSongFilter reads the filter and sort query parameters of GET /songs,
+// which GET /export shares. Pagination is left to the caller.
+func parseSongFilter(c *gin.Context) (repository.SongFilter, *apperr.Error) {
+	filter := repository.SongFilter{
+		Group: c.Query("group"),
+		Title: c.Query("song"),
+		Text:  c.Query("text"),
+		Link:  c.Query("link"),
+	}
+
+	var err *apperr.Error
+	switch match := c.DefaultQuery("match", "fuzzy"); match {
+	case "fuzzy":
+	case "exact":
+		filter.Exact = true
+	default:
+		return filter, apperr.InvalidParam("match", "expected exact or fuzzy")
+	}
+
+	if value := c.Query("group_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || id == 0 {
+			return filter, apperr.InvalidParam("group_id", "expected a positive integer")
+		}
+		filter.GroupID = uint(id)
+	}
+
+	if filter.ReleaseDate, err = dateParam(c, "release_date"); err != nil {
+		return filter, err
+	}
+	if filter.ReleaseFrom, err = dateParam(c, "release_from"); err != nil {
+		return filter, err
+	}
+	if filter.ReleaseTo, err = dateParam(c, "release_to"); err != nil {
+		return filter, err
+	}
+
+	if value := c.Query("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 || year > 9999 {
+			return filter, apperr.InvalidParam("year", "expected a year such as 2006")
+		}
+		if filter.ReleaseFrom != nil || filter.ReleaseTo != nil {
+			return filter, apperr.InvalidParam("year", "cannot be combined with release_from or release_to")
+		}
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(1, 0, 0).Add(-time.Nanosecond)
+		filter.ReleaseFrom, filter.ReleaseTo = &from, &to
+	}
+
+	if filter.CreatedFrom, err = timeParam(c, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = timeParam(c, "created_to"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedFrom, err = timeParam(c, "updated_from"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedTo, err = timeParam(c, "updated_to"); err != nil {
+		return filter, err
+	}
+
+	for _, window := range []struct {
+		from, to *time.Time
+		name     string
+	}{
+		{filter.ReleaseFrom, filter.ReleaseTo, "release_to"},
+		{filter.CreatedFrom, filter.CreatedTo, "created_to"},
+		{filter.UpdatedFrom, filter.UpdatedTo, "updated_to"},
+	} {
+		if window.from != nil && window.to != nil && window.to.Before(*window.from) {
+			return filter, apperr.InvalidParam(window.name, "is before the start of the range")
+		}
+	}
+
+	sort, sortErr := repository.ParseSongSort(c.Query("sort"))
+	if sortErr != nil {
+		return filter, apperr.InvalidParam("sort", sortErr.Error())
+	}
+	filter.Sort = sort
+
+	return filter, nil
+}
+
+// maxLimit bounds the limit query parameter so that a single request cannot
+// read a whole table.
+const maxLimit = 100
+
+// pageParams reads the page and limit query parameters shared by every
+// list endpoint, rejecting anything that is not a positive integer and
+// limits above maxLimit.
+func pageParams(c *gin.Context) (int, int, *apperr.Error) {
+	page, err := intParam(c, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := intParam(c, "limit", 10)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit > maxLimit {
+		return 0, 0, apperr.InvalidParam("limit", fmt.Sprintf("must be at most %d", maxLimit))
+	}
+	return page, limit, nil
+}
+
+func intParam(c *gin.Context, name string, defaultValue int) (int, *apperr.Error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, apperr.InvalidParam(name, "expected a positive integer")
+	}
+	return number, nil
+}
+
+// dateParam parses a DD.MM.YYYY or YYYY-MM-DD date, the formats release
+// dates are accepted in everywhere in the API.
+func dateParam(c *gin.Context, name string) (*time.Time, *apperr.Error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := models.ParseReleaseDate(value)
+	if err != nil {
+		return nil, apperr.InvalidParam(name, "expected DD.MM.YYYY or YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+func timeParam(c *gin.Context, name string) (*time.Time, *apperr.Error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, apperr.InvalidParam(name, "expected an RFC 3339 timestamp such as 2024-01-31T18:00:00Z")
+	}
+	return &t, nil
+}
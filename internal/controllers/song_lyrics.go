@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type songSection struct {
+	SectionID string `json:"id" example:"verse-2"`
+	models.SongSection
+	LastLine int `json:"last_line" example:"8"`
+}
+
+type songSections struct {
+	SongID    uint          `json:"song_id" example:"1"`
+	LineCount int           `json:"line_count" example:"16"`
+	Sections  []songSection `json:"sections"`
+}
+
+type songLine struct {
+	Number  int    `json:"number" example:"5"`
+	Text    string `json:"text" example:"Ooh baby, don't you know I suffer?"`
+	Section string `json:"section" example:"verse-2"`
+}
+
+type songLines struct {
+	SongID uint       `json:"song_id" example:"1"`
+	From   int        `json:"from" example:"5"`
+	To     int        `json:"to" example:"8"`
+	Total  int        `json:"total" example:"16"`
+	Lines  []songLine `json:"lines"`
+}
+
+// GetSongSections godoc
+// @Summary Get the sections of a song text
+// @Description List the verses, choruses and other sections of the song text with their line numbers.
+// @Description Sections marked with a header line such as [Chorus] or "Куплет 2:" take their type from it,
+// @Description otherwise repeated blocks are choruses and the others verses.
+// @Tags Lyrics
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} songSections "Sections retrieved successfully"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Invalid song ID format"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/sections [get]
+func (sc *SongController) GetSongSections(c *gin.Context) {
+	song, sections, ok := sc.songSections(c)
+	if !ok {
+		return
+	}
+
+	resp := songSections{SongID: song.ID, Sections: make([]songSection, len(sections))}
+	for i, section := range sections {
+		resp.Sections[i] = newSongSection(section)
+		resp.LineCount += len(section.Lines)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetSongSection godoc
+// @Summary Get a single section of a song text
+// @Description Get a section by type and ordinal, e.g. verse-3 for the third verse. Without an ordinal the first section of the type is returned.
+// @Tags Lyrics
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param section path string true "Section, e.g. verse-3 or chorus"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} songSection "Section retrieved successfully"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Invalid song ID or section format"
+// @Failure 404 {object} apperr.Problem "Song or section not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/sections/{section} [get]
+func (sc *SongController) GetSongSection(c *gin.Context) {
+	sectionType, ordinal, valid := lyrics.ParseSectionID(c.Param("section"))
+	if !valid {
+		apperr.Abort(c, apperr.InvalidParam("section", "expected a type and ordinal such as verse-3"))
+		return
+	}
+
+	_, sections, ok := sc.songSections(c)
+	if !ok {
+		return
+	}
+
+	for _, section := range sections {
+		if section.Type == sectionType && section.Ordinal == ordinal {
+			c.JSON(http.StatusOK, newSongSection(section))
+			return
+		}
+	}
+	apperr.Abort(c, apperr.NotFound("section not found"))
+}
+
+// GetSongLines godoc
+// @Summary Get a range of lines of a song text
+// @Description Get lines by number, counted from 1 across the whole song. Section headers are not counted.
+// @Tags Lyrics
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param from query int false "First line" default(1)
+// @Param to query int false "Last line, defaults to the end of the song"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} songLines "Lines retrieved successfully"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Invalid song ID or line range"
+// @Failure 404 {object} apperr.Problem "Song not found or range past the end of the text"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/lines [get]
+func (sc *SongController) GetSongLines(c *gin.Context) {
+	from, err := intParam(c, "from", 1)
+	if err != nil {
+		apperr.Abort(c, err)
+		return
+	}
+	to, err := intParam(c, "to", 0)
+	if err != nil {
+		apperr.Abort(c, err)
+		return
+	}
+	if to != 0 && to < from {
+		apperr.Abort(c, apperr.InvalidParam("to", "is before from"))
+		return
+	}
+
+	song, sections, ok := sc.songSections(c)
+	if !ok {
+		return
+	}
+
+	lines := make([]songLine, 0)
+	for _, section := range sections {
+		for i, text := range section.Lines {
+			lines = append(lines, songLine{
+				Number:  section.FirstLine + i,
+				Text:    text,
+				Section: lyrics.SectionID(section.Type, section.Ordinal),
+			})
+		}
+	}
+
+	total := len(lines)
+	if from > total {
+		apperr.Abort(c, apperr.NotFound("no lines found for requested range"))
+		return
+	}
+	if to == 0 || to > total {
+		to = total
+	}
+
+	c.JSON(http.StatusOK, songLines{
+		SongID: song.ID,
+		From:   from,
+		To:     to,
+		Total:  total,
+		Lines:  lines[from-1 : to],
+	})
+}
+
+// songSections loads the song given by the id path parameter and its
+// sections, and sets the ETag header. It responds itself and returns false on
+// failure or when the client's copy is not modified.
+func (sc *SongController) songSections(c *gin.Context) (*models.Song, []models.SongSection, bool) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	// The song may be deleted between the two reads, so both report a
+	// missing song the same way.
+	var sections []models.SongSection
+	song, err := sc.songs.GetByID(ctx, id)
+	if err == nil {
+		if notModified(c, song) {
+			return nil, nil, false
+		}
+		sections, err = sc.songs.Sections(ctx, song.ID)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Info("song not found", slog.Any("id", id))
+		apperr.Abort(c, apperr.NotFound("song not found"))
+		return nil, nil, false
+	}
+	if err != nil {
+		log.Error("failed to query song sections", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return nil, nil, false
+	}
+	return song, sections, true
+}
+
+func newSongSection(section models.SongSection) songSection {
+	return songSection{
+		SectionID:   lyrics.SectionID(section.Type, section.Ordinal),
+		SongSection: section,
+		LastLine:    section.FirstLine + len(section.Lines) - 1,
+	}
+}
@@ -1,115 +1,148 @@
 package controllers
 
 import (
+	"effectiveMobileTask/internal/apperr"
 	"effectiveMobileTask/internal/models"
-	"effectiveMobileTask/internal/storage/database"
+	"effectiveMobileTask/internal/storage/repository"
 	"effectiveMobileTask/lib/logger"
 	"errors"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 	"log/slog"
 	"net/http"
-	"strconv"
-	"time"
 )
 
 // UpdateSong godoc
 // @Summary Update an existing song
-// @Description Update song information by ID (supports partial updates)
+// @Description Update song information by ID (supports partial updates). If-Match may hold the ETag of the song
+// @Description from GET /songs/{id} and is required when SERVER_REQUIRE_IF_MATCH is true; the response carries the
+// @Description new ETag.
+// @Description A new group_name renames the group of the song, and so of all its songs, or with group_mode=reassign
+// @Description moves just this song to the group with that name, creating it when there is none. The song and its
+// @Description group are updated in a single transaction.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of the song from GET /songs/{id}"
+// @Param group_mode query string false "How a new group_name is applied" Enums(rename, reassign) default(rename)
 // @Param song body models.SongUpdate true "Song Update Information (supports partial updates)"
 // @Success 200 {object} map[string]string "Song updated successfully"
-// @Failure 400 {object} map[string]string "Invalid song data or ID format"
-// @Failure 404 {object} map[string]string "Song not found"
-// @Failure 500 {object} map[string]string "Internal server error - database error"
+// @Header 200 {string} ETag "New ETag of the song"
+// @Failure 400 {object} apperr.Problem "Invalid song data or ID format"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 409 {object} apperr.Problem "Another group has the new name, the group was deleted meanwhile, or the song changed by a concurrent request without If-Match"
+// @Failure 412 {object} apperr.Problem "If-Match does not match the current ETag"
+// @Failure 428 {object} apperr.Problem "If-Match header missing while SERVER_REQUIRE_IF_MATCH is true"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
 // @Router /songs/{id} [patch]
-func UpdateSong(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+func (sc *SongController) UpdateSong(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
+	}
+
+	groupMode := repository.SongGroupMode(c.DefaultQuery("group_mode", string(repository.SongGroupRename)))
+	if groupMode != repository.SongGroupRename && groupMode != repository.SongGroupReassign {
+		apperr.Abort(c, apperr.InvalidParam("group_mode", "expected rename or reassign"))
+		return
+	}
+
+	song, err := sc.songs.GetByID(ctx, id)
 	if err != nil {
-		logger.Error("invalid song ID format", slog.Any("id", c.Param("id")))
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid song ID format"})
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Error("song not found", slog.Any("id", id))
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+		log.Error("failed to fetch song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
 		return
 	}
 
-	db := database.DbConnect()
-	var song models.Song
-	if err := db.First(&song, id).Error; err != nil {
-		logger.Error("song not found", slog.Any("id", id))
-		c.JSON(http.StatusNotFound, gin.H{"message": "song not found"})
+	if !sc.checkIfMatch(c, song) {
 		return
 	}
 
 	var updateData models.SongUpdate
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		logger.Error("invalid song update data", slog.Any("error", err))
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request body"})
+		log.Error("invalid song update data", slog.Any("error", err))
+		apperr.Abort(c, apperr.InvalidBody(err))
 		return
 	}
 
-	updates := make(map[string]interface{})
 	updatedFields := make([]string, 0)
 
+	var groupName string
 	if updateData.GroupName != nil {
-		var group models.Group
-		if err := db.First(&group, song.GroupId).Error; err != nil {
-			logger.Error("group not found", slog.Any("group_id", song.GroupId))
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "group not found"})
-			return
-		}
-
-		if err := db.Model(&group).Update("name", *updateData.GroupName).Error; err != nil {
-			logger.Error("failed to update group name", slog.Any("group_id", song.GroupId))
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to update group name"})
-			return
-		}
-
+		groupName = *updateData.GroupName
 		updatedFields = append(updatedFields, "group_name")
 	}
 
 	if updateData.Song != nil {
-		updates["title"] = *updateData.Song
+		song.Title = *updateData.Song
 		updatedFields = append(updatedFields, "title")
 	}
 
 	if updateData.ReleaseDate != nil {
-		// (день.месяц.год)
-		date, err := time.Parse("02.01.2006", *updateData.ReleaseDate)
-		if err != nil {
-			logger.Error("invalid release date format", slog.Any("error", err))
-			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid release date format, expected format: DD.MM.YYYY"})
-			return
-		}
-		updates["release_date"] = date
+		// Checked by the releasedate rule when binding.
+		song.ReleaseDate, _ = models.ParseReleaseDate(*updateData.ReleaseDate)
 		updatedFields = append(updatedFields, "release_date")
 	}
 
 	if updateData.Text != nil {
-		updates["text"] = *updateData.Text
+		song.Text = *updateData.Text
 		updatedFields = append(updatedFields, "text")
 	}
 
 	if updateData.Link != nil {
-		updates["link"] = *updateData.Link
+		song.Link = *updateData.Link
 		updatedFields = append(updatedFields, "link")
 	}
 
-	if len(updates) > 0 {
-		if err := db.Model(&song).Updates(updates).Error; err != nil {
-			logger.Error("failed to update song", slog.Any("id", id), slog.Any("error", err))
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
-			return
-		}
+	if updateData.Language != nil {
+		song.Language = *updateData.Language
+		updatedFields = append(updatedFields, "language")
 	}
 
 	if len(updatedFields) == 0 {
+		c.Header("ETag", songETag(song))
 		c.JSON(http.StatusOK, gin.H{"message": "no updates provided"})
 		return
 	}
 
-	logger.Info("song and/or group updated successfully", slog.Any("id", id), slog.Any("updated_fields", updatedFields))
+	// A renamed group changes the song too, so its version is incremented
+	// in that case as well.
+	if err := sc.songs.UpdateWithGroup(ctx, song, groupName, groupMode); err != nil {
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Info("song changed concurrently", slog.Any("id", id))
+			apperr.Abort(c, versionMismatch(c))
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			log.Info("group name already taken", slog.Any("id", id), slog.Any("group", groupName))
+			apperr.Abort(c, apperr.Conflict("group %q already exists, use group_mode=reassign to move the song to it", groupName))
+			return
+		}
+		if errors.Is(err, repository.ErrGroupNotFound) {
+			log.Info("group of song deleted concurrently", slog.Any("id", id), slog.Any("group_id", song.GroupId))
+			apperr.Abort(c, apperr.Conflict("the group of song %d was deleted, use group_mode=reassign to move the song to group %q", id, groupName))
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info("song deleted concurrently", slog.Any("id", id))
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+		log.Error("failed to update song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+	c.Header("ETag", songETag(song))
+
+	log.Info("song and/or group updated successfully", slog.Any("id", id), slog.Any("updated_fields", updatedFields))
 	c.JSON(http.StatusOK, gin.H{
 		"message":        "song updated successfully",
 		"updated_fields": updatedFields,
@@ -118,43 +151,67 @@ func UpdateSong(c *gin.Context) {
 
 // DeleteSong godoc
 // @Summary Delete a song
-// @Description Delete a song by its ID
+// @Description Move a song to the trash by its ID; it can be restored with POST /songs/{id}/restore.
+// @Description If-Match may hold the ETag of the song and is required when SERVER_REQUIRE_IF_MATCH is true.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of the song from GET /songs/{id}"
 // @Success 200 {object} map[string]string "Song deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid song ID format"
-// @Failure 404 {object} map[string]string "Song not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperr.Problem "Invalid song ID format"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 412 {object} apperr.Problem "If-Match does not match the current ETag"
+// @Failure 428 {object} apperr.Problem "If-Match header missing while SERVER_REQUIRE_IF_MATCH is true"
+// @Failure 500 {object} apperr.Problem "Internal server error"
 // @Router /songs/{id} [delete]
-func DeleteSong(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		logger.Error("invalid song ID format", slog.Any("id", c.Param("id")))
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid song ID format"})
+func (sc *SongController) DeleteSong(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
 		return
 	}
 
-	db := database.DbConnect()
-	var song models.Song
-	if err := db.First(&song, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Info("song already deleted or does not exist", slog.Any("id", id))
-			c.JSON(http.StatusNotFound, gin.H{"message": "song already deleted or does not exist"})
+	song, err := sc.songs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info("song already deleted or does not exist", slog.Any("id", id))
+			apperr.Abort(c, apperr.NotFound("song already deleted or does not exist"))
 			return
 		}
-		logger.Error("failed to fetch song", slog.Any("id", id), slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+		log.Error("failed to fetch song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
 		return
 	}
 
-	if err := db.Delete(&song).Error; err != nil {
-		logger.Error("failed to delete song", slog.Any("id", id), slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+	if !sc.checkIfMatch(c, song) {
+		return
+	}
+
+	// Without If-Match the song is deleted whatever its version.
+	var version uint
+	if c.GetHeader("If-Match") != "" {
+		version = song.Version
+	}
+
+	if err := sc.songs.Delete(ctx, song.ID, version); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info("song already deleted or does not exist", slog.Any("id", id))
+			apperr.Abort(c, apperr.NotFound("song already deleted or does not exist"))
+			return
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Info("song changed concurrently", slog.Any("id", id))
+			apperr.Abort(c, versionMismatch(c))
+			return
+		}
+		log.Error("failed to delete song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
 		return
 	}
 
-	logger.Info("song deleted successfully", slog.Any("id", id))
+	log.Info("song deleted successfully", slog.Any("id", id))
 	c.JSON(http.StatusOK, gin.H{"message": "song deleted successfully"})
 }
@@ -1,15 +1,15 @@
 package controllers
 
 import (
-	"effectiveMobileTask/internal/models"
-	"effectiveMobileTask/internal/storage/database"
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/storage/repository"
 	"effectiveMobileTask/lib/logger"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
-	"time"
 )
 
 // GetSongs godoc
@@ -19,131 +19,236 @@ import (
 // @Accept json
 // @Produce json
 // @Param group query string false "Filter by Group Name"
+// @Param group_id query int false "Filter by Group ID"
 // @Param song query string false "Filter by Song Title"
-// @Param release_date query string false "Filter by Release Date (format: DD.MM.YYYY)"
+// @Param release_date query string false "Filter by Release Date (format: DD.MM.YYYY or YYYY-MM-DD)"
+// @Param release_from query string false "Released on or after (format: DD.MM.YYYY or YYYY-MM-DD)"
+// @Param release_to query string false "Released on or before (format: DD.MM.YYYY or YYYY-MM-DD)"
+// @Param year query int false "Released in the year; cannot be combined with release_from/release_to"
 // @Param text query string false "Filter by Text"
 // @Param link query string false "Filter by Link"
+// @Param match query string false "How group, song, text and link are compared: fuzzy is a case-insensitive substring, exact the whole value" Enums(fuzzy, exact) default(fuzzy)
+// @Param created_from query string false "Created at or after (RFC 3339)"
+// @Param created_to query string false "Created at or before (RFC 3339)"
+// @Param updated_from query string false "Updated at or after (RFC 3339)"
+// @Param updated_to query string false "Updated at or before (RFC 3339)"
+// @Param sort query string false "Comma separated sort fields, - for descending: id, title, group, release_date, created_at, updated_at" example(release_date,-title)
 // @Param page query int false "Page number for pagination" default(1)
-// @Param limit query int false "Number of items per page" default(10)
+// @Param limit query int false "Number of items per page" default(10) minimum(1) maximum(100)
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor; pass it empty for the first page. Switches the response to a songPage envelope and page is ignored"
+// @Param total query bool false "Include the total count in a cursor page"
 // @Success 200 {array} models.Song "Songs retrieved successfully"
-// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
-// @Failure 404 {object} map[string]string "No songs found matching criteria"
-// @Failure 500 {object} map[string]string "Internal server error - database error"
+// @Success 200 {object} songPage "Songs page retrieved successfully (cursor mode)"
+// @Failure 400 {object} apperr.Problem "Bad request - the parameter field names the invalid parameter"
+// @Failure 404 {object} apperr.Problem "No songs found matching criteria (page mode only)"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
 // @Router /songs [get]
-func GetSongs(c *gin.Context) {
-	db := database.DbConnect()
-	var songs []models.Song
+func (sc *SongController) GetSongs(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
 
-	group := c.Query("group")
-	song := c.Query("song")
-	releaseDate := c.Query("release_date")
-	text := c.Query("text")
-	link := c.Query("link")
-	page := c.DefaultQuery("page", "1")
-	limit := c.DefaultQuery("limit", "10")
+	filter, paramErr := parseSongFilter(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+	page, limit, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+	filter.Offset, filter.Limit = (page-1)*limit, limit
 
-	pageNumber, err := strconv.Atoi(page)
-	if err != nil || pageNumber < 1 {
-		pageNumber = 1
+	if token, ok := c.GetQuery("cursor"); ok {
+		sc.getSongPage(c, filter, token)
+		return
 	}
 
-	limitNumber, err := strconv.Atoi(limit)
-	if err != nil || limitNumber < 1 {
-		limitNumber = 10
+	songs, err := sc.songs.List(ctx, filter)
+	if err != nil {
+		log.Error("failed to query songs", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
 	}
 
-	query := db.Model(&models.Song{}).
-		Select("songs.*, groups.name AS group_name").
-		Joins("JOIN groups ON songs.group_id = groups.id")
+	if len(songs) == 0 {
+		apperr.Abort(c, apperr.NotFound("no songs found matching criteria"))
+		return
+	}
+
+	log.Info("Songs retrieved successfully", slog.Int("count", len(songs)))
+	c.JSON(http.StatusOK, songs)
+}
 
-	if group != "" {
-		query = query.Where("groups.name ILIKE ?", "%"+group+"%")
+// getSongPage serves GET /songs in cursor mode. One extra song is fetched to
+// find out whether there is a page beyond the requested one.
+func (sc *SongController) getSongPage(c *gin.Context, filter repository.SongFilter, token string) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	cursor, err := decodeCursor(token, filter.Sort)
+	if err != nil {
+		apperr.Abort(c, apperr.InvalidParam("cursor", err.Error()))
+		return
+	}
+	firstPage := cursor == nil
+	if firstPage {
+		cursor = &repository.SongCursor{}
+	}
+
+	withTotal := false
+	if value, ok := c.GetQuery("total"); ok {
+		withTotal, err = strconv.ParseBool(value)
+		if err != nil {
+			apperr.Abort(c, apperr.InvalidParam("total", "expected true or false"))
+			return
+		}
+	}
+
+	limit := filter.Limit
+	if !firstPage {
+		filter.Cursor = cursor
+	}
+	filter.Offset = 0
+	filter.Limit = limit + 1
+
+	songs, err := sc.songs.List(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			apperr.Abort(c, apperr.InvalidParam("cursor", err.Error()))
+			return
+		}
+		log.Error("failed to query songs", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
 	}
 
-	if song != "" {
-		query = query.Where("songs.song ILIKE ?", "%"+song+"%")
+	hasMore := len(songs) > limit
+	if hasMore {
+		if cursor.Before {
+			songs = songs[1:]
+		} else {
+			songs = songs[:limit]
+		}
 	}
 
-	if releaseDate != "" {
-		date, err := time.Parse("02.01.2006", releaseDate)
-		if err == nil {
-			query = query.Where("songs.release_date = ?", date)
+	page := songPage{Items: songs}
+	if len(songs) > 0 {
+		first, last := songs[0], songs[len(songs)-1]
+		if (cursor.Before && hasMore) || (!cursor.Before && token != "") {
+			page.PrevCursor = encodeCursor(repository.NewSongCursor(first, filter.Sort, true), filter.Sort)
+		}
+		if (!cursor.Before && hasMore) || cursor.Before {
+			page.NextCursor = encodeCursor(repository.NewSongCursor(last, filter.Sort, false), filter.Sort)
 		}
 	}
 
-	if text != "" {
-		query = query.Where("songs.text ILIKE ?", "%"+text+"%")
+	if withTotal {
+		total, err := sc.songs.Count(ctx, filter)
+		if err != nil {
+			log.Error("failed to count songs", slog.Any("error", err))
+			apperr.Abort(c, apperr.Internal(err))
+			return
+		}
+		page.Total = &total
 	}
 
-	if link != "" {
-		query = query.Where("songs.link ILIKE ?", "%"+link+"%")
+	log.Info("Songs retrieved successfully", slog.Int("count", len(songs)))
+	c.JSON(http.StatusOK, page)
+}
+
+// GetSong godoc
+// @Summary Get a song by ID
+// @Description Get a single song. The ETag header holds its version; send it in If-Match to PATCH or DELETE the
+// @Description song, or in If-None-Match to get 304 Not Modified while the song stays the same.
+// @Tags Songs
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} models.Song "Song retrieved successfully"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Invalid song ID format"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id} [get]
+func (sc *SongController) GetSong(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
 	}
 
-	offset := (pageNumber - 1) * limitNumber
-	query = query.Offset(offset).Limit(limitNumber)
+	song, err := sc.songs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+		log.Error("failed to query song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
 
-	if err := query.Find(&songs).Error; err != nil {
-		logger.Error("failed to query songs", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to query songs"})
+	if notModified(c, song) {
 		return
 	}
 
-	if len(songs) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"message": "no songs found matching criteria"})
+	group, err := sc.groups.GetByID(ctx, song.GroupId)
+	if err != nil {
+		log.Error("failed to query group of song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
 		return
 	}
+	song.GroupName = group.Name
 
-	logger.Info("Songs retrieved successfully", slog.Int("count", len(songs)))
-	c.JSON(http.StatusOK, songs)
+	c.JSON(http.StatusOK, song)
 }
 
 // GetSongText godoc
 // @Summary Get song text by ID with pagination
-// @Description Retrieve song text for a specific song ID with pagination support
+// @Description Retrieve song text for a specific song ID with pagination support. Each item of text is one
+// @Description section of the song, see /songs/{id}/sections.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
 // @Param page query int false "Page number for text pagination" default(1)
-// @Param limit query int false "Number of text lines per page" default(10)
+// @Param limit query int false "Number of text lines per page" default(10) minimum(1) maximum(100)
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} map[string]interface{} "Song text retrieved successfully"
-// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
-// @Failure 404 {object} map[string]string "Song or page not found"
-// @Failure 500 {object} map[string]string "Internal server error - database error"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Bad request - invalid ID, page or limit"
+// @Failure 404 {object} apperr.Problem "Song or page not found"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
 // @Router /songs/{id}/text [get]
-func GetSongText(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		logger.Error("invalid song ID format", slog.Any("id", c.Param("id")))
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid song ID format"})
-		return
-	}
+func (sc *SongController) GetSongText(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
 
-	db := database.DbConnect()
-	var song models.Song
-
-	if err := db.Unscoped().First(&song, id).Error; err != nil {
-		logger.Error("failed to query song", slog.Any("id", id))
-		c.JSON(http.StatusNotFound, gin.H{"message": "song not found"})
+	song, sections, ok := sc.songSections(c)
+	if !ok {
 		return
 	}
+	id := song.ID
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	page, limit, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 {
-		limit = 10
+	text := make([]string, len(sections))
+	for i, section := range sections {
+		text[i] = lyrics.Section{Header: section.Header, Lines: section.Lines}.Text()
 	}
 
-	text := strings.Split(song.Text, "\n\n")
-
 	totalText := len(text)
 	if totalText == 0 {
-		logger.Error("no text found for song with id ", slog.Any("id", id))
-		c.JSON(http.StatusNotFound, gin.H{"message": "text not found"})
+		log.Error("no text found for song with id ", slog.Any("id", id))
+		apperr.Abort(c, apperr.NotFound("text not found"))
 		return
 	}
 
@@ -151,8 +256,8 @@ func GetSongText(c *gin.Context) {
 	endOfIndex := beginOfIndex + limit
 
 	if beginOfIndex >= totalText {
-		logger.Error("page out of range for song id", slog.Any("id", id), slog.Any("page", page))
-		c.JSON(http.StatusNotFound, gin.H{"message": "no text found for requested page"})
+		log.Error("page out of range for song id", slog.Any("id", id), slog.Any("page", page))
+		apperr.Abort(c, apperr.NotFound("no text found for requested page"))
 		return
 	}
 
@@ -170,6 +275,6 @@ func GetSongText(c *gin.Context) {
 		"totalPage": (totalText + limit - 1) / limit,
 	}
 
-	logger.Info("retrieved text for song id ", slog.Any("id", id), slog.Any("page", page))
+	log.Info("retrieved text for song id ", slog.Any("id", id), slog.Any("page", page))
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/audit"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type revisionDiff struct {
+	SongID  uint                          `json:"song_id" example:"1"`
+	From    uint                          `json:"from" example:"3"`
+	To      uint                          `json:"to" example:"7"`
+	Changes map[string]models.FieldChange `json:"changes"`
+	// TextDiff compares the texts line by line when they differ.
+	TextDiff []lyrics.DiffLine `json:"text_diff,omitempty"`
+	// TextDiffOmitted tells that the texts differ in too many lines to be
+	// compared; their values are still in Changes.
+	TextDiffOmitted bool `json:"text_diff_omitted,omitempty"`
+}
+
+// ListSongRevisions godoc
+// @Summary List the revision history of a song
+// @Description Every change of the song, newest first: who made it (the X-Actor header), when, the changed fields
+// @Description with their values before and after, and the state of the song afterwards. Deleted songs keep their history.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10) minimum(1) maximum(100)
+// @Success 200 {array} models.Revision "Revisions retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid song ID, page or limit"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/revisions [get]
+func (sc *SongController) ListSongRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
+	}
+
+	pageNumber, limitNumber, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+
+	revisions, err := sc.revisions.List(ctx, models.RevisionEntitySong, id, (pageNumber-1)*limitNumber, limitNumber)
+	if err != nil {
+		log.Error("failed to query revisions", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	// Songs added before revisions were recorded have none.
+	if len(revisions) == 0 && pageNumber == 1 {
+		if _, err := sc.songs.GetByID(ctx, id); errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetSongRevision godoc
+// @Summary Get a revision of a song
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param revision path int true "Revision ID"
+// @Success 200 {object} models.Revision "Revision retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid song or revision ID"
+// @Failure 404 {object} apperr.Problem "Revision not found for the song"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/revisions/{revision} [get]
+func (sc *SongController) GetSongRevision(c *gin.Context) {
+	revision, ok := sc.songRevision(c, "revision", c.Param("revision"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffSongRevisions godoc
+// @Summary Compare two revisions of a song
+// @Description List the fields that differ between the states of the song after two revisions, and compare the
+// @Description texts line by line unless they differ in too many lines. Without "to" the revision is compared with
+// @Description the latest one.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param from query int true "Revision ID"
+// @Param to query int false "Revision ID, defaults to the latest revision"
+// @Success 200 {object} revisionDiff "Difference retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid song or revision ID"
+// @Failure 404 {object} apperr.Problem "Revision not found for the song"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/revisions/diff [get]
+func (sc *SongController) DiffSongRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	if c.Query("from") == "" {
+		apperr.Abort(c, apperr.InvalidParam("from", "is required"))
+		return
+	}
+	from, ok := sc.songRevision(c, "from", c.Query("from"))
+	if !ok {
+		return
+	}
+
+	var to *models.Revision
+	if value := c.Query("to"); value != "" {
+		if to, ok = sc.songRevision(c, "to", value); !ok {
+			return
+		}
+	} else {
+		latest, err := sc.revisions.List(ctx, models.RevisionEntitySong, from.EntityID, 0, 1)
+		if err != nil || len(latest) == 0 {
+			log.Error("failed to query latest revision", slog.Any("id", from.EntityID), slog.Any("error", err))
+			apperr.Abort(c, apperr.Internal(err))
+			return
+		}
+		to = &latest[0]
+	}
+
+	diff := revisionDiff{
+		SongID:  from.EntityID,
+		From:    from.ID,
+		To:      to.ID,
+		Changes: models.DiffStates(from.State, to.State),
+	}
+	if _, ok := diff.Changes["text"]; ok {
+		diff.TextDiff, ok = lyrics.Diff(from.State["text"], to.State["text"])
+		diff.TextDiffOmitted = !ok
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RevertSong godoc
+// @Summary Revert a song to a revision
+// @Description Restore the title, group, release date, text, link and language the song had after the revision.
+// @Description The song goes back to the same group even if it has been renamed since; a deleted group is recreated.
+// @Description The revert is recorded as a new revision. If-Match is checked as for PATCH /songs/{id}.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param revision path int true "Revision ID"
+// @Param If-Match header string false "ETag of the song from GET /songs/{id}"
+// @Success 200 {object} models.Song "Song reverted successfully"
+// @Header 200 {string} ETag "New ETag of the song"
+// @Failure 400 {object} apperr.Problem "Invalid song or revision ID"
+// @Failure 404 {object} apperr.Problem "Song or revision not found"
+// @Failure 409 {object} apperr.Problem "Song or its group changed by a concurrent request"
+// @Failure 412 {object} apperr.Problem "If-Match does not match the current ETag"
+// @Failure 428 {object} apperr.Problem "If-Match header missing while SERVER_REQUIRE_IF_MATCH is true"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/revisions/{revision}/revert [post]
+func (sc *SongController) RevertSong(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	revision, ok := sc.songRevision(c, "revision", c.Param("revision"))
+	if !ok {
+		return
+	}
+
+	song, err := sc.songs.GetByID(ctx, revision.EntityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song not found, restore it from the trash first"))
+			return
+		}
+		log.Error("failed to fetch song", slog.Any("id", revision.EntityID), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	if !sc.checkIfMatch(c, song) {
+		return
+	}
+
+	state := revision.State
+	groupID, err := strconv.ParseUint(state["group_id"], 10, 0)
+	if err != nil || groupID == 0 || state["group_name"] == "" {
+		err = fmt.Errorf("revision %d does not record the group of the song", revision.ID)
+		log.Error("failed to revert song", slog.Any("id", song.ID), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+	song.GroupId = uint(groupID)
+	song.Title = state["song"]
+	song.ReleaseDate = time.Time{}
+	if state["release_date"] != "" {
+		if song.ReleaseDate, err = models.ParseReleaseDate(state["release_date"]); err != nil {
+			apperr.Abort(c, apperr.Internal(err))
+			return
+		}
+	}
+	song.Text = state["text"]
+	song.Link = state["link"]
+	if state["language"] != "" {
+		song.Language = state["language"]
+	}
+
+	// The song goes back to the group it had, even if that group has been
+	// renamed since; a deleted group is recreated under its old name.
+	if err := sc.songs.UpdateWithGroup(audit.WithRevert(ctx, revision.ID), song, state["group_name"], repository.SongGroupRestore); err != nil {
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Info("song changed concurrently", slog.Any("id", song.ID))
+			apperr.Abort(c, versionMismatch(c))
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			log.Info("group created concurrently", slog.Any("id", song.ID), slog.Any("group", state["group_name"]))
+			apperr.Abort(c, apperr.Conflict("group %q was created by a concurrent request, try again", state["group_name"]))
+			return
+		}
+		log.Error("failed to revert song", slog.Any("id", song.ID), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("song reverted", slog.Any("id", song.ID), slog.Any("revision", revision.ID))
+	c.Header("ETag", songETag(song))
+	c.JSON(http.StatusOK, song)
+}
+
+// songRevision loads the revision given by value, a path or query parameter
+// called name, and makes sure it belongs to the song given by the id path
+// parameter. It responds with an error itself and returns false on failure.
+func (sc *SongController) songRevision(c *gin.Context, name, value string) (*models.Revision, bool) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	songID, ok := songIDParam(c)
+	if !ok {
+		return nil, false
+	}
+	revisionID, err := strconv.Atoi(value)
+	if err != nil || revisionID < 1 {
+		apperr.Abort(c, apperr.InvalidParam(name, "expected a positive integer"))
+		return nil, false
+	}
+
+	revision, err := sc.revisions.GetByID(ctx, uint(revisionID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("revision %d not found", revisionID))
+			return nil, false
+		}
+		log.Error("failed to query revision", slog.Any("revision", revisionID), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return nil, false
+	}
+	if revision.EntityType != models.RevisionEntitySong || revision.EntityID != songID {
+		apperr.Abort(c, apperr.NotFound("revision %d not found for song %d", revisionID, songID))
+		return nil, false
+	}
+	return revision, true
+}
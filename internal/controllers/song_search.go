@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type searchHit struct {
+	Song           models.Song `json:"song"`
+	Rank           float64     `json:"rank" example:"0.4"`
+	TitleHighlight string      `json:"title_highlight" example:"Supermassive Black <mark>Hole</mark>"`
+	// Snippet is the verse with the most matches; Verse is its index in the
+	// text, as used by /songs/{id}/text.
+	Snippet string `json:"snippet" example:"Glaciers melting in the dead of night\nAnd the superstars sucked into the super<mark>massive</mark>"`
+	Verse   int    `json:"verse" example:"0"`
+}
+
+// SearchSongs godoc
+// @Summary Full-text search over song titles and lyrics
+// @Description Search songs by words in the title and the text, ranked by relevance. Matches are wrapped in <mark></mark>.
+// @Description mode=web accepts "quoted phrases", or and -word; mode=phrase matches the words in order; mode=prefix matches word beginnings.
+// @Tags Songs
+// @Produce json
+// @Param q query string true "Search query"
+// @Param mode query string false "Query mode" Enums(web, phrase, prefix) default(web)
+// @Param language query string false "Only search songs indexed with this language, e.g. english, and parse the query with it. Without it the query is parsed as simple"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10) minimum(1) maximum(100)
+// @Success 200 {array} searchHit "Songs found"
+// @Failure 400 {object} apperr.Problem "Bad request - invalid parameters"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /search [get]
+func (sc *SongController) SearchSongs(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		apperr.Abort(c, apperr.InvalidParam("q", "is required"))
+		return
+	}
+
+	mode := repository.SearchMode(c.DefaultQuery("mode", string(repository.SearchModeWeb)))
+	switch mode {
+	case repository.SearchModeWeb, repository.SearchModePhrase, repository.SearchModePrefix:
+	default:
+		apperr.Abort(c, apperr.InvalidParam("mode", "expected one of: web, phrase, prefix"))
+		return
+	}
+
+	language := c.Query("language")
+	if language != "" && !models.IsSupportedLanguage(language) {
+		apperr.Abort(c, apperr.InvalidParam("language", "unsupported language "+language))
+		return
+	}
+
+	page, limit, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+
+	results, err := sc.songs.Search(ctx, repository.SearchQuery{
+		Text:     q,
+		Mode:     mode,
+		Language: language,
+		Offset:   (page - 1) * limit,
+		Limit:    limit,
+	})
+	if err != nil {
+		log.Error("failed to search songs", slog.Any("query", q), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	hits := make([]searchHit, 0, len(results))
+	for _, result := range results {
+		verse, snippet := bestVerse(result.HighlightedText)
+		hits = append(hits, searchHit{
+			Song:           result.Song,
+			Rank:           result.Rank,
+			TitleHighlight: result.HighlightedTitle,
+			Snippet:        snippet,
+			Verse:          verse,
+		})
+	}
+
+	log.Info("songs searched", slog.Any("query", q), slog.Int("count", len(hits)))
+	c.JSON(http.StatusOK, hits)
+}
+
+// bestVerse returns the section of the highlighted text with the most
+// matches. The text is split by lyrics.Parse, as for /songs/{id}/text, so
+// that the index points at the same section there. Without matches in the
+// text the first section is returned.
+func bestVerse(highlighted string) (int, string) {
+	sections := lyrics.Parse(highlighted)
+	if len(sections) == 0 {
+		return 0, ""
+	}
+
+	best, bestCount := 0, 0
+	for i, section := range sections {
+		if count := strings.Count(section.Text(), repository.HighlightStart); count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+	return best, sections[best].Text()
+}
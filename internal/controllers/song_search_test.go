@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"testing"
+)
+
+func TestBestVerse(t *testing.T) {
+	tests := []struct {
+		name        string
+		highlighted string
+		wantVerse   int
+		wantSnippet string
+	}{
+		{"empty", "", 0, ""},
+		{"no matches", "a\nb\n\nc", 0, "a\nb"},
+		{"most matches", "<mark>a</mark>\n\nb <mark>c</mark>\n<mark>c</mark>", 1, "b <mark>c</mark>\n<mark>c</mark>"},
+		{"first of equals", "<mark>a</mark>\n\n<mark>b</mark>", 0, "<mark>a</mark>"},
+		// Extra blank lines and Windows line endings do not shift the
+		// index away from the section /songs/{id}/text serves.
+		{"blank lines", "a\r\n\r\n\r\n\r\nb\r\n\r\n<mark>c</mark>\r\n", 2, "<mark>c</mark>"},
+		{"header", "a\n\n\n[Chorus]\n<mark>b</mark>", 1, "[Chorus]\n<mark>b</mark>"},
+	}
+	for _, tt := range tests {
+		verse, snippet := bestVerse(tt.highlighted)
+		if verse != tt.wantVerse || snippet != tt.wantSnippet {
+			t.Errorf("%s: bestVerse = %d, %q, want %d, %q", tt.name, verse, snippet, tt.wantVerse, tt.wantSnippet)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"bytes"
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maxLRCSize limits uploaded LRC files; real ones are a few kilobytes.
+const maxLRCSize = 1 << 20
+
+type lyricsLine struct {
+	// Time and TimeMs are only set for synced lyrics.
+	Time   string `json:"time,omitempty" example:"00:12.34"`
+	TimeMs *int64 `json:"time_ms,omitempty" example:"12340"`
+	Text   string `json:"text" example:"Ooh baby, don't you know I suffer?"`
+}
+
+type songLyrics struct {
+	SongID uint              `json:"song_id" example:"1"`
+	Synced bool              `json:"synced" example:"true"`
+	Tags   map[string]string `json:"tags,omitempty"`
+	Lines  []lyricsLine      `json:"lines"`
+}
+
+// GetSongLyrics godoc
+// @Summary Get song lyrics as LRC, JSON or plain text
+// @Description format=lrc returns the synced lyrics as an LRC file and fails with 404 when none were uploaded.
+// @Description format=json returns the lines with their timings when the song has synced lyrics, format=txt the plain text.
+// @Tags Lyrics
+// @Produce json
+// @Produce plain
+// @Param id path int true "Song ID"
+// @Param format query string false "Representation" Enums(json, lrc, txt) default(json)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} songLyrics "Lyrics retrieved successfully"
+// @Header 200 {string} ETag "ETag of the song"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} apperr.Problem "Invalid song ID or format"
+// @Failure 404 {object} apperr.Problem "Song or synced lyrics not found"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/lyrics [get]
+func (sc *SongController) GetSongLyrics(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "lrc" && format != "txt" {
+		apperr.Abort(c, apperr.InvalidParam("format", "expected lrc, json or txt"))
+		return
+	}
+
+	song, err := sc.songs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+		log.Error("failed to query song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	if notModified(c, song) {
+		return
+	}
+
+	if format == "txt" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(song.Text))
+		return
+	}
+
+	synced, err := sc.songs.SyncedLyrics(ctx, song.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Error("failed to query synced lyrics", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	if format == "lrc" {
+		if synced == nil {
+			apperr.Abort(c, apperr.NotFound("song has no synced lyrics"))
+			return
+		}
+		sc.writeLRC(c, song, synced)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSongLyrics(song, synced))
+}
+
+// UploadSongLyrics godoc
+// @Summary Upload synced lyrics as an LRC file
+// @Description Replace the song text with the lines of the LRC file and store their timings. Lines must start with
+// @Description [mm:ss.xx] timestamps in chronological order; [ar:], [ti:] and other tags are kept. Empty timed lines
+// @Description separate sections. The file is sent as the request body or as the "file" field of a multipart form.
+// @Description Changing the text with PATCH /songs/{id} later drops the timings. If-Match is checked as for
+// @Description PATCH /songs/{id}.
+// @Tags Lyrics
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of the song from GET /songs/{id}"
+// @Param lyrics body string true "LRC file"
+// @Success 200 {object} songLyrics "Lyrics stored successfully"
+// @Header 200 {string} ETag "New ETag of the song"
+// @Failure 400 {object} apperr.Problem "Invalid song ID or LRC file, line tells where"
+// @Failure 404 {object} apperr.Problem "Song not found"
+// @Failure 409 {object} apperr.Problem "Song changed by a concurrent request without If-Match"
+// @Failure 412 {object} apperr.Problem "If-Match does not match the current ETag"
+// @Failure 413 {object} apperr.Problem "LRC file too large"
+// @Failure 428 {object} apperr.Problem "If-Match header missing while SERVER_REQUIRE_IF_MATCH is true"
+// @Failure 500 {object} apperr.Problem "Internal server error"
+// @Router /songs/{id}/lyrics [put]
+func (sc *SongController) UploadSongLyrics(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
+	}
+
+	body, err := readLRCUpload(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperr.Abort(c, apperr.TooLarge("LRC file is larger than %d bytes", maxLRCSize))
+			return
+		}
+		apperr.Abort(c, &apperr.Error{Code: apperr.CodeBadRequest, Detail: "failed to read LRC file", Err: err})
+		return
+	}
+
+	lrc, err := lyrics.ParseLRC(bytes.NewReader(body))
+	if err != nil {
+		var lrcErr *lyrics.LRCError
+		if errors.As(err, &lrcErr) {
+			problem := apperr.Validation("invalid LRC file: " + lrcErr.Error())
+			if lrcErr.Line > 0 {
+				problem.With("line", lrcErr.Line)
+			}
+			apperr.Abort(c, problem)
+			return
+		}
+		apperr.Abort(c, &apperr.Error{Code: apperr.CodeBadRequest, Detail: "failed to read LRC file", Err: err})
+		return
+	}
+
+	song, err := sc.songs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song not found"))
+			return
+		}
+		log.Error("failed to query song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	if !sc.checkIfMatch(c, song) {
+		return
+	}
+
+	synced := &models.SyncedLyrics{Tags: lrc.Tags, Lines: make([]models.TimedLine, len(lrc.Lines))}
+	for i, line := range lrc.Lines {
+		synced.Lines[i] = models.TimedLine{TimeMs: line.Time.Milliseconds(), Text: line.Text}
+	}
+	song.Text = lrc.PlainText()
+
+	if err := sc.songs.SaveSyncedLyrics(ctx, song, synced); err != nil {
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			log.Info("song changed concurrently", slog.Any("id", id))
+			apperr.Abort(c, versionMismatch(c))
+			return
+		}
+		log.Error("failed to save synced lyrics", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("synced lyrics uploaded", slog.Any("id", id), slog.Int("lines", len(synced.Lines)))
+	c.Header("ETag", songETag(song))
+	c.JSON(http.StatusOK, newSongLyrics(song, synced))
+}
+
+func readLRCUpload(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLRCSize)
+
+	if c.ContentType() != "multipart/form-data" {
+		return io.ReadAll(c.Request.Body)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// writeLRC sends the synced lyrics as an LRC file. The title and artist
+// tags default to the song and its group.
+func (sc *SongController) writeLRC(c *gin.Context, song *models.Song, synced *models.SyncedLyrics) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	lrc := lyrics.LRC{Tags: make(map[string]string), Lines: make([]lyrics.TimedLine, len(synced.Lines))}
+	for key, value := range synced.Tags {
+		lrc.Tags[key] = value
+	}
+	if _, ok := lrc.Tags["ti"]; !ok {
+		lrc.Tags["ti"] = song.Title
+	}
+	if _, ok := lrc.Tags["ar"]; !ok {
+		group, err := sc.groups.GetByID(ctx, song.GroupId)
+		if err != nil {
+			log.Error("failed to query group", slog.Any("group_id", song.GroupId), slog.Any("error", err))
+			apperr.Abort(c, apperr.Internal(err))
+			return
+		}
+		lrc.Tags["ar"] = group.Name
+	}
+	for i, line := range synced.Lines {
+		lrc.Lines[i] = lyrics.TimedLine{Time: time.Duration(line.TimeMs) * time.Millisecond, Text: line.Text}
+	}
+
+	var b bytes.Buffer
+	if err := lrc.Format(&b); err != nil {
+		log.Error("failed to format LRC file", slog.Any("id", song.ID), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="song-%d.lrc"`, song.ID))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", b.Bytes())
+}
+
+// newSongLyrics returns the timed lines of the synced lyrics, or the lines of
+// the song text without timings when synced is nil.
+func newSongLyrics(song *models.Song, synced *models.SyncedLyrics) songLyrics {
+	resp := songLyrics{SongID: song.ID, Lines: make([]lyricsLine, 0)}
+	if synced == nil {
+		for _, line := range lyrics.Lines(lyrics.Parse(song.Text)) {
+			resp.Lines = append(resp.Lines, lyricsLine{Text: line})
+		}
+		return resp
+	}
+
+	resp.Synced = true
+	resp.Tags = synced.Tags
+	for _, line := range synced.Lines {
+		timeMs := line.TimeMs
+		resp.Lines = append(resp.Lines, lyricsLine{
+			Time:   lyrics.FormatTimestamp(time.Duration(timeMs) * time.Millisecond),
+			TimeMs: &timeMs,
+			Text:   line.Text,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ListTrash godoc
+// @Summary List deleted songs
+// @Description Retrieve songs in the trash, most recently deleted first
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10) minimum(1) maximum(100)
+// @Success 200 {array} models.Song "Deleted songs retrieved successfully"
+// @Failure 400 {object} apperr.Problem "Invalid page or limit"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /songs/trash [get]
+func (sc *SongController) ListTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	pageNumber, limitNumber, paramErr := pageParams(c)
+	if paramErr != nil {
+		apperr.Abort(c, paramErr)
+		return
+	}
+
+	songs, err := sc.songs.ListDeleted(ctx, (pageNumber-1)*limitNumber, limitNumber)
+	if err != nil {
+		log.Error("failed to query deleted songs", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("deleted songs retrieved successfully", slog.Int("count", len(songs)))
+	c.JSON(http.StatusOK, songs)
+}
+
+// RestoreSong godoc
+// @Summary Restore a deleted song
+// @Description Take a song out of the trash. If its group was deleted too, the group is restored,
+// @Description or the song joins a group with the same name that exists by now.
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} models.Song "Song restored successfully"
+// @Header 200 {string} ETag "New ETag of the song"
+// @Failure 400 {object} apperr.Problem "Invalid song ID format"
+// @Failure 404 {object} apperr.Problem "Song is not in the trash"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /songs/{id}/restore [post]
+func (sc *SongController) RestoreSong(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := songIDParam(c)
+	if !ok {
+		return
+	}
+
+	song, err := sc.songs.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperr.Abort(c, apperr.NotFound("song is not in the trash"))
+			return
+		}
+		log.Error("failed to restore song", slog.Any("id", id), slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	log.Info("song restored successfully", slog.Any("id", id))
+	c.Header("ETag", songETag(song))
+	c.JSON(http.StatusOK, song)
+}
+
+// PurgeTrash godoc
+// @Summary Permanently remove old trash items
+// @Description Permanently delete songs and groups that were deleted earlier than older_than ago
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param older_than query string false "Minimum time in the trash, e.g. 720h; defaults to the configured retention"
+// @Success 200 {object} map[string]interface{} "Number of purged songs and groups"
+// @Failure 400 {object} apperr.Problem "Invalid older_than value"
+// @Failure 500 {object} apperr.Problem "Internal server error - database error"
+// @Router /songs/trash [delete]
+func (sc *SongController) PurgeTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	retention := sc.cfg.TrashRetention
+	if value := c.Query("older_than"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			apperr.Abort(c, apperr.InvalidParam("older_than", "expected a duration such as 720h"))
+			return
+		}
+		retention = parsed
+	}
+
+	songs, groups, err := repository.PurgeTrash(ctx, sc.songs, sc.groups, time.Now().Add(-retention))
+	if err != nil {
+		log.Error("failed to purge trash", slog.Any("error", err))
+		apperr.Abort(c, apperr.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "trash purged successfully",
+		"purged_songs":  songs,
+		"purged_groups": groups,
+	})
+}
@@ -0,0 +1,31 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/models"
+	"errors"
+)
+
+const ProviderAPI = "api"
+
+// APIEnricher asks the external music info service for song details.
+type APIEnricher struct {
+	client *infoapi.Client
+}
+
+func NewAPIEnricher(client *infoapi.Client) *APIEnricher {
+	return &APIEnricher{client: client}
+}
+
+func (e *APIEnricher) Name() string {
+	return ProviderAPI
+}
+
+func (e *APIEnricher) Enrich(ctx context.Context, group, song string) (models.SongDetail, error) {
+	detail, err := e.client.GetSongDetail(ctx, group, song)
+	if errors.Is(err, infoapi.ErrNotFound) {
+		return models.SongDetail{}, ErrNotFound
+	}
+	return detail, err
+}
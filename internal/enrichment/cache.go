@@ -0,0 +1,111 @@
+package enrichment
+
+import (
+	"container/list"
+	"context"
+	"effectiveMobileTask/internal/models"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CachedEnricher keeps recent lookups of another enricher in memory. Only
+// successful lookups are cached, so a song that was not found or failed is
+// looked up again next time. The least recently used entry is evicted once
+// size entries are held.
+type CachedEnricher struct {
+	next SongEnricher
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// recent orders the entries from most to least recently used.
+	recent *list.List
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cacheEntry struct {
+	key     string
+	detail  models.SongDetail
+	expires time.Time
+}
+
+func NewCachedEnricher(next SongEnricher, size int, ttl time.Duration) *CachedEnricher {
+	return &CachedEnricher{
+		next:    next,
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+func (e *CachedEnricher) Name() string {
+	return e.next.Name()
+}
+
+func (e *CachedEnricher) Enrich(ctx context.Context, group, song string) (models.SongDetail, error) {
+	key := strings.ToLower(group) + "\x00" + strings.ToLower(song)
+
+	if detail, ok := e.get(key); ok {
+		e.hits.Add(1)
+		return detail, nil
+	}
+	e.misses.Add(1)
+
+	detail, err := e.next.Enrich(ctx, group, song)
+	if err != nil {
+		return detail, err
+	}
+	e.put(key, detail)
+	return detail, nil
+}
+
+// Stats returns the number of lookups answered from the cache and passed on
+// to the next enricher.
+func (e *CachedEnricher) Stats() (hits, misses uint64) {
+	return e.hits.Load(), e.misses.Load()
+}
+
+func (e *CachedEnricher) get(key string) (models.SongDetail, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	element, ok := e.entries[key]
+	if !ok {
+		return models.SongDetail{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if e.now().After(entry.expires) {
+		e.recent.Remove(element)
+		delete(e.entries, key)
+		return models.SongDetail{}, false
+	}
+	e.recent.MoveToFront(element)
+	return entry.detail, true
+}
+
+func (e *CachedEnricher) put(key string, detail models.SongDetail) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry := &cacheEntry{key: key, detail: detail, expires: e.now().Add(e.ttl)}
+	if element, ok := e.entries[key]; ok {
+		element.Value = entry
+		e.recent.MoveToFront(element)
+		return
+	}
+
+	e.entries[key] = e.recent.PushFront(entry)
+	for e.recent.Len() > e.size {
+		oldest := e.recent.Back()
+		e.recent.Remove(oldest)
+		delete(e.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
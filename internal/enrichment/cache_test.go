@@ -0,0 +1,108 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestCache returns a cache over next whose clock is moved by the
+// returned function.
+func newTestCache(next SongEnricher, size int, ttl time.Duration) (*CachedEnricher, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCachedEnricher(next, size, ttl)
+	cache.now = func() time.Time { return now }
+	return cache, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCachedEnricherHitsAndMisses(t *testing.T) {
+	next := &stubEnricher{name: "api", detail: models.SongDetail{Text: "text"}}
+	cache, _ := newTestCache(next, 10, time.Hour)
+	ctx := context.Background()
+
+	for _, lookup := range [][2]string{{"Muse", "Uprising"}, {"muse", "UPRISING"}, {"Muse", "Starlight"}, {"Muse", "Uprising"}} {
+		detail, err := cache.Enrich(ctx, lookup[0], lookup[1])
+		if err != nil || detail.Text != "text" {
+			t.Fatalf("Enrich(%q, %q) = %+v, %v", lookup[0], lookup[1], detail, err)
+		}
+	}
+
+	// Keys ignore case, so only the first lookup of each song misses.
+	if hits, misses := cache.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("Stats() = %d hits, %d misses, want 2 and 2", hits, misses)
+	}
+	if next.calls != 2 {
+		t.Errorf("next enricher called %d times, want 2", next.calls)
+	}
+	if cache.Name() != "api" {
+		t.Errorf("Name() = %q, want the name of the next enricher", cache.Name())
+	}
+}
+
+func TestCachedEnricherExpires(t *testing.T) {
+	next := &stubEnricher{detail: models.SongDetail{Text: "old"}}
+	cache, advance := newTestCache(next, 10, time.Hour)
+	ctx := context.Background()
+
+	_, _ = cache.Enrich(ctx, "Muse", "Uprising")
+	next.detail.Text = "new"
+
+	advance(time.Hour)
+	if detail, _ := cache.Enrich(ctx, "Muse", "Uprising"); detail.Text != "old" {
+		t.Errorf("text within the TTL = %q, want the cached one", detail.Text)
+	}
+
+	advance(time.Second)
+	if detail, _ := cache.Enrich(ctx, "Muse", "Uprising"); detail.Text != "new" {
+		t.Errorf("text after the TTL = %q, want a fresh lookup", detail.Text)
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 2 {
+		t.Errorf("Stats() = %d hits, %d misses, want 1 and 2", hits, misses)
+	}
+}
+
+func TestCachedEnricherEvictsLeastRecentlyUsed(t *testing.T) {
+	next := &stubEnricher{detail: models.SongDetail{Text: "text"}}
+	cache, _ := newTestCache(next, 2, time.Hour)
+	ctx := context.Background()
+
+	_, _ = cache.Enrich(ctx, "Muse", "Uprising")
+	_, _ = cache.Enrich(ctx, "Muse", "Starlight")
+	// Using Uprising again leaves Starlight as the least recently used.
+	_, _ = cache.Enrich(ctx, "Muse", "Uprising")
+	_, _ = cache.Enrich(ctx, "Muse", "Madness")
+	if next.calls != 3 {
+		t.Fatalf("next enricher called %d times, want 3", next.calls)
+	}
+
+	_, _ = cache.Enrich(ctx, "Muse", "Uprising")
+	if next.calls != 3 {
+		t.Errorf("Uprising was evicted")
+	}
+	_, _ = cache.Enrich(ctx, "Muse", "Starlight")
+	if next.calls != 4 {
+		t.Errorf("Starlight was not evicted")
+	}
+}
+
+func TestCachedEnricherDoesNotCacheErrors(t *testing.T) {
+	for _, err := range []error{ErrNotFound, errors.New("upstream down")} {
+		next := &stubEnricher{err: err}
+		cache, _ := newTestCache(next, 10, time.Hour)
+		ctx := context.Background()
+
+		if _, got := cache.Enrich(ctx, "Muse", "Uprising"); !errors.Is(got, err) {
+			t.Fatalf("Enrich() error = %v, want %v", got, err)
+		}
+		next.err = nil
+		next.detail = models.SongDetail{Text: "text"}
+		if detail, got := cache.Enrich(ctx, "Muse", "Uprising"); got != nil || detail.Text != "text" {
+			t.Errorf("Enrich() after %v = %+v, %v, want a fresh lookup", err, detail, got)
+		}
+		if hits, misses := cache.Stats(); hits != 0 || misses != 2 {
+			t.Errorf("Stats() after %v = %d hits, %d misses, want 0 and 2", err, hits, misses)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package enrichment
+
+import (
+	"bufio"
+	"context"
+	"effectiveMobileTask/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const ProviderCatalog = "catalog"
+
+type catalogEntry struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"release_date"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// CatalogEnricher serves song details from a local file. The file may hold a
+// single JSON object, a JSON array of objects or newline-delimited objects.
+// It is read once when the enricher is created.
+type CatalogEnricher struct {
+	entries map[string]catalogEntry
+}
+
+func NewCatalogEnricher(path string) (*CatalogEnricher, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open catalog %s: %w", path, err)
+	}
+	defer file.Close()
+
+	entries, err := readCatalog(file)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog %s: %w", path, err)
+	}
+
+	catalog := &CatalogEnricher{entries: make(map[string]catalogEntry, len(entries))}
+	for _, entry := range entries {
+		catalog.entries[catalogKey(entry.Group, entry.Song)] = entry
+	}
+	return catalog, nil
+}
+
+func (e *CatalogEnricher) Name() string {
+	return ProviderCatalog
+}
+
+func (e *CatalogEnricher) Enrich(_ context.Context, group, song string) (models.SongDetail, error) {
+	entry, ok := e.entries[catalogKey(group, song)]
+	if !ok {
+		return models.SongDetail{}, ErrNotFound
+	}
+
+	return models.SongDetail{
+		GroupName:   entry.Group,
+		SongName:    entry.Song,
+		ReleaseDate: entry.ReleaseDate,
+		Text:        entry.Text,
+		Link:        entry.Link,
+	}, nil
+}
+
+func readCatalog(r io.Reader) ([]catalogEntry, error) {
+	reader := bufio.NewReader(r)
+
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	if first == '[' {
+		var entries []catalogEntry
+		if err := decoder.Decode(&entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	// A single object and NDJSON are both a stream of JSON values.
+	var entries []catalogEntry
+	for {
+		var entry catalogEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			return b, reader.UnreadByte()
+		}
+	}
+}
+
+func catalogKey(group, song string) string {
+	return strings.ToLower(group) + "\x00" + strings.ToLower(song)
+}
@@ -0,0 +1,127 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"log/slog"
+)
+
+const (
+	FieldReleaseDate = "release_date"
+	FieldText        = "text"
+	FieldLink        = "link"
+)
+
+var fields = []string{FieldReleaseDate, FieldText, FieldLink}
+
+// Chain combines several enrichers. Each field is taken from the first
+// provider in its preference list that returns a non-empty value; fields
+// without an explicit preference follow the chain order. Providers are only
+// called when a field still needs a value from them.
+type Chain struct {
+	providers   []SongEnricher
+	preferences map[string][]string
+}
+
+func NewChain(providers []SongEnricher, preferences map[string][]string) *Chain {
+	return &Chain{
+		providers:   providers,
+		preferences: preferences,
+	}
+}
+
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+func (c *Chain) Enrich(ctx context.Context, group, song string) (models.SongDetail, error) {
+	byName := make(map[string]SongEnricher, len(c.providers))
+	order := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		byName[provider.Name()] = provider
+		order = append(order, provider.Name())
+	}
+
+	results := make(map[string]models.SongDetail)
+	failures := make(map[string]error)
+
+	lookup := func(name string) (models.SongDetail, bool) {
+		if detail, ok := results[name]; ok {
+			return detail, true
+		}
+		if _, failed := failures[name]; failed {
+			return models.SongDetail{}, false
+		}
+
+		provider, ok := byName[name]
+		if !ok {
+			return models.SongDetail{}, false
+		}
+
+		detail, err := provider.Enrich(ctx, group, song)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				logger.FromContext(ctx).Error("song enrichment provider failed", slog.String("provider", name), slog.Any("error", err))
+			}
+			failures[name] = err
+			return models.SongDetail{}, false
+		}
+		results[name] = detail
+		return detail, true
+	}
+
+	merged := models.SongDetail{GroupName: group, SongName: song}
+	for _, field := range fields {
+		preference, ok := c.preferences[field]
+		if !ok || len(preference) == 0 {
+			preference = order
+		}
+
+		for _, name := range preference {
+			detail, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if value := fieldValue(detail, field); value != "" {
+				setFieldValue(&merged, field, value)
+				break
+			}
+		}
+	}
+
+	if len(results) > 0 {
+		return merged, nil
+	}
+
+	for _, err := range failures {
+		if !errors.Is(err, ErrNotFound) {
+			return models.SongDetail{}, err
+		}
+	}
+	return models.SongDetail{}, ErrNotFound
+}
+
+func fieldValue(detail models.SongDetail, field string) string {
+	switch field {
+	case FieldReleaseDate:
+		return detail.ReleaseDate
+	case FieldText:
+		return detail.Text
+	case FieldLink:
+		return detail.Link
+	}
+	return ""
+}
+
+func setFieldValue(detail *models.SongDetail, field, value string) {
+	switch field {
+	case FieldReleaseDate:
+		detail.ReleaseDate = value
+	case FieldText:
+		detail.Text = value
+	case FieldLink:
+		detail.Link = value
+	}
+}
@@ -0,0 +1,134 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// stubEnricher returns a fixed detail or error and counts its calls.
+type stubEnricher struct {
+	name   string
+	detail models.SongDetail
+	err    error
+	calls  int
+}
+
+func (e *stubEnricher) Name() string {
+	return e.name
+}
+
+func (e *stubEnricher) Enrich(context.Context, string, string) (models.SongDetail, error) {
+	e.calls++
+	return e.detail, e.err
+}
+
+func TestChainEnrich(t *testing.T) {
+	errUpstream := errors.New("upstream down")
+
+	tests := []struct {
+		name        string
+		api         *stubEnricher
+		catalog     *stubEnricher
+		preferences map[string][]string
+		want        models.SongDetail
+		wantErr     error
+		// wantCatalogCalls tells whether the catalog had to be asked.
+		wantCatalogCalls int
+	}{
+		{
+			name:             "first provider has everything",
+			api:              &stubEnricher{detail: models.SongDetail{ReleaseDate: "07.09.2009", Text: "api text", Link: "api link"}},
+			catalog:          &stubEnricher{detail: models.SongDetail{Text: "catalog text"}},
+			want:             models.SongDetail{ReleaseDate: "07.09.2009", Text: "api text", Link: "api link"},
+			wantCatalogCalls: 0,
+		},
+		{
+			name:             "missing fields come from the next provider",
+			api:              &stubEnricher{detail: models.SongDetail{ReleaseDate: "07.09.2009"}},
+			catalog:          &stubEnricher{detail: models.SongDetail{ReleaseDate: "01.01.2000", Text: "catalog text", Link: "catalog link"}},
+			want:             models.SongDetail{ReleaseDate: "07.09.2009", Text: "catalog text", Link: "catalog link"},
+			wantCatalogCalls: 1,
+		},
+		{
+			name:             "field preferences override the chain order",
+			api:              &stubEnricher{detail: models.SongDetail{ReleaseDate: "07.09.2009", Text: "api text", Link: "api link"}},
+			catalog:          &stubEnricher{detail: models.SongDetail{Text: "catalog text"}},
+			preferences:      map[string][]string{FieldText: {"catalog", "api"}},
+			want:             models.SongDetail{ReleaseDate: "07.09.2009", Text: "catalog text", Link: "api link"},
+			wantCatalogCalls: 1,
+		},
+		{
+			name:             "failing provider is skipped",
+			api:              &stubEnricher{err: errUpstream},
+			catalog:          &stubEnricher{detail: models.SongDetail{Text: "catalog text"}},
+			want:             models.SongDetail{Text: "catalog text"},
+			wantCatalogCalls: 1,
+		},
+		{
+			name:             "not found anywhere",
+			api:              &stubEnricher{err: ErrNotFound},
+			catalog:          &stubEnricher{err: ErrNotFound},
+			wantErr:          ErrNotFound,
+			wantCatalogCalls: 1,
+		},
+		{
+			name:             "failure wins over not found",
+			api:              &stubEnricher{err: errUpstream},
+			catalog:          &stubEnricher{err: ErrNotFound},
+			wantErr:          errUpstream,
+			wantCatalogCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.api.name, tt.catalog.name = "api", "catalog"
+			chain := NewChain([]SongEnricher{tt.api, tt.catalog}, tt.preferences)
+
+			got, err := chain.Enrich(context.Background(), "Muse", "Uprising")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				tt.want.GroupName, tt.want.SongName = "Muse", "Uprising"
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Enrich = %+v, want %+v", got, tt.want)
+				}
+			}
+			if tt.api.calls > 1 || tt.catalog.calls != tt.wantCatalogCalls {
+				t.Errorf("providers called %d and %d times, want at most once and %d", tt.api.calls, tt.catalog.calls, tt.wantCatalogCalls)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	stored := models.Song{Text: "old text", Link: "old link", ReleaseDate: time.Date(2006, 6, 19, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name     string
+		detail   models.SongDetail
+		wantErr  error
+		wantText string
+		wantLink string
+		wantDate string
+	}{
+		{"all fields", models.SongDetail{ReleaseDate: "07.09.2009", Text: "new", Link: "link"}, nil, "new", "link", "2009-09-07"},
+		{"iso date", models.SongDetail{ReleaseDate: "2009-09-07"}, nil, "old text", "old link", "2009-09-07"},
+		{"empty fields", models.SongDetail{}, nil, "old text", "old link", "2006-06-19"},
+		{"bad date", models.SongDetail{ReleaseDate: "September 2009", Text: "new"}, ErrInvalidReleaseDate, "new", "old link", "2006-06-19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := stored
+			if err := Apply(&song, tt.detail); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply error = %v, want %v", err, tt.wantErr)
+			}
+			if song.Text != tt.wantText || song.Link != tt.wantLink || song.ReleaseDate.Format("2006-01-02") != tt.wantDate {
+				t.Errorf("Apply = %q, %q, %s, want %q, %q, %s", song.Text, song.Link, song.ReleaseDate.Format("2006-01-02"), tt.wantText, tt.wantLink, tt.wantDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound           = errors.New("song details not found")
+	ErrInvalidReleaseDate = errors.New("invalid release date")
+)
+
+// SongEnricher looks up additional song details (release date, text, link)
+// for a group and song title.
+type SongEnricher interface {
+	Name() string
+	Enrich(ctx context.Context, group, song string) (models.SongDetail, error)
+}
+
+// Apply copies the enriched fields that are not empty into the song, so a
+// provider that knows only some of them does not wipe the others. The
+// release date may be DD.MM.YYYY or YYYY-MM-DD; one that does not parse is
+// reported as ErrInvalidReleaseDate after the other fields have been
+// applied, leaving the song's release date untouched.
+func Apply(song *models.Song, detail models.SongDetail) error {
+	if detail.Text != "" {
+		song.Text = detail.Text
+	}
+	if detail.Link != "" {
+		song.Link = detail.Link
+	}
+	if detail.ReleaseDate == "" {
+		return nil
+	}
+
+	releaseDate, err := models.ParseReleaseDate(detail.ReleaseDate)
+	if err != nil {
+		return fmt.Errorf("%w %q", ErrInvalidReleaseDate, detail.ReleaseDate)
+	}
+	song.ReleaseDate = releaseDate
+	return nil
+}
@@ -0,0 +1,53 @@
+package enrichment
+
+import (
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/infoapi"
+	"fmt"
+)
+
+// NewFromConfig builds the enrichment chain in the order given by
+// config.EnrichmentConfig.Providers. The api provider uses the given client.
+func NewFromConfig(cfg config.Config, client *infoapi.Client) (SongEnricher, error) {
+	providers := make([]SongEnricher, 0, len(cfg.Enrichment.Providers))
+	known := make(map[string]bool)
+
+	for _, name := range cfg.Enrichment.Providers {
+		var provider SongEnricher
+		switch name {
+		case ProviderAPI:
+			provider = NewAPIEnricher(client)
+		case ProviderCatalog:
+			catalog, err := NewCatalogEnricher(cfg.Enrichment.CatalogPath)
+			if err != nil {
+				return nil, err
+			}
+			provider = catalog
+		case ProviderNoop:
+			provider = NewNoopEnricher()
+		default:
+			return nil, fmt.Errorf("unknown enrichment provider %q", name)
+		}
+		providers = append(providers, provider)
+		known[name] = true
+	}
+
+	preferences := map[string][]string{
+		FieldReleaseDate: cfg.Enrichment.ReleaseDateFrom,
+		FieldText:        cfg.Enrichment.TextFrom,
+		FieldLink:        cfg.Enrichment.LinkFrom,
+	}
+	for field, names := range preferences {
+		for _, name := range names {
+			if !known[name] {
+				return nil, fmt.Errorf("enrichment field %s refers to provider %q which is not in the chain", field, name)
+			}
+		}
+	}
+
+	chain := NewChain(providers, preferences)
+	if cfg.Enrichment.CacheSize <= 0 || cfg.Enrichment.CacheTTL <= 0 {
+		return chain, nil
+	}
+	return NewCachedEnricher(chain, cfg.Enrichment.CacheSize, cfg.Enrichment.CacheTTL), nil
+}
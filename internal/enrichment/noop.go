@@ -0,0 +1,22 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+)
+
+const ProviderNoop = "noop"
+
+type NoopEnricher struct{}
+
+func NewNoopEnricher() *NoopEnricher {
+	return &NoopEnricher{}
+}
+
+func (e *NoopEnricher) Name() string {
+	return ProviderNoop
+}
+
+func (e *NoopEnricher) Enrich(_ context.Context, group, song string) (models.SongDetail, error) {
+	return models.SongDetail{GroupName: group, SongName: song}, nil
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"effectiveMobileTask/internal/infoapi"
+	"effectiveMobileTask/internal/storage/database"
+	"errors"
+	"fmt"
+)
+
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Run: func(ctx context.Context) (string, error) {
+			return "", db.PingContext(ctx)
+		},
+	}
+}
+
+// MigrationsCheck fails while migrations are pending, e.g. when the schema
+// is older than the binary and DB_AUTO_MIGRATE is off.
+func MigrationsCheck(migrator *database.Migrator) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Run: func(ctx context.Context) (string, error) {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return "", err
+			}
+			if pending > 0 {
+				return "", fmt.Errorf("%d migration(s) pending", pending)
+			}
+			return "all migrations applied", nil
+		},
+	}
+}
+
+// UpstreamCheck reports the circuit breaker of the info API. The upstream is
+// not probed: the breaker already tracks whether recent requests failed.
+// Songs can still be added while it is open, so the check is not critical.
+func UpstreamCheck(client *infoapi.Client) Check {
+	return Check{
+		Name: "enrichment_api",
+		Run: func(context.Context) (string, error) {
+			state := client.BreakerState()
+			if state == infoapi.StateOpen {
+				return "", errors.New("circuit " + state.String())
+			}
+			return "circuit " + state.String(), nil
+		},
+	}
+}
@@ -0,0 +1,100 @@
+// Package health checks the dependencies the service needs to take traffic.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+	// StatusDegraded is a non-critical component that is down.
+	StatusDegraded Status = "degraded"
+)
+
+// Check reports the state of one dependency. Run returns an optional detail
+// message, or an error when the dependency is unusable.
+type Check struct {
+	Name string
+	// Critical checks make the service not ready when they fail; the others
+	// are only reported as degraded.
+	Critical bool
+	Run      func(ctx context.Context) (string, error)
+}
+
+type Component struct {
+	Status    Status `json:"status" example:"up"`
+	Message   string `json:"message,omitempty" example:"all migrations applied"`
+	LatencyMs int64  `json:"latency_ms" example:"2"`
+}
+
+type Report struct {
+	Ready      bool                 `json:"ready" example:"true"`
+	Draining   bool                 `json:"draining,omitempty"`
+	Components map[string]Component `json:"components"`
+}
+
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewChecker returns a checker running the checks with the given timeout
+// each.
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// SetDraining marks the service as shutting down; it is not ready from then
+// on.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Check runs all checks concurrently.
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Ready:      !c.draining.Load(),
+		Draining:   c.draining.Load(),
+		Components: make(map[string]Component, len(c.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			message, err := check.Run(ctx)
+			component := Component{Status: StatusUp, Message: message, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				component.Status = StatusDegraded
+				if check.Critical {
+					component.Status = StatusDown
+				}
+				component.Message = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name] = component
+			if component.Status == StatusDown {
+				report.Ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func staticCheck(name string, critical bool, message string, err error) Check {
+	return Check{Name: name, Critical: critical, Run: func(context.Context) (string, error) { return message, err }}
+}
+
+func TestCheckerCheck(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		checks    []Check
+		wantReady bool
+		want      map[string]Status
+	}{
+		{
+			name:      "all up",
+			checks:    []Check{staticCheck("database", true, "", nil), staticCheck("enrichment_api", false, "circuit closed", nil)},
+			wantReady: true,
+			want:      map[string]Status{"database": StatusUp, "enrichment_api": StatusUp},
+		},
+		{
+			name:      "critical check fails",
+			checks:    []Check{staticCheck("database", true, "", errDown), staticCheck("enrichment_api", false, "", nil)},
+			wantReady: false,
+			want:      map[string]Status{"database": StatusDown, "enrichment_api": StatusUp},
+		},
+		{
+			name:      "non-critical check fails",
+			checks:    []Check{staticCheck("database", true, "", nil), staticCheck("enrichment_api", false, "", errDown)},
+			wantReady: true,
+			want:      map[string]Status{"database": StatusUp, "enrichment_api": StatusDegraded},
+		},
+		{
+			name:      "no checks",
+			wantReady: true,
+			want:      map[string]Status{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewChecker(time.Second, tt.checks...).Check(context.Background())
+			if report.Ready != tt.wantReady || report.Draining {
+				t.Errorf("ready = %t, draining = %t, want ready = %t", report.Ready, report.Draining, tt.wantReady)
+			}
+			if len(report.Components) != len(tt.want) {
+				t.Fatalf("components = %+v, want %v", report.Components, tt.want)
+			}
+			for name, status := range tt.want {
+				component := report.Components[name]
+				if component.Status != status {
+					t.Errorf("%s = %s, want %s", name, component.Status, status)
+				}
+				if status != StatusUp && component.Message != errDown.Error() {
+					t.Errorf("%s message = %q, want the error", name, component.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	hanging := Check{Name: "database", Critical: true, Run: func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+
+	start := time.Now()
+	report := NewChecker(20*time.Millisecond, hanging).Check(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Check took %v despite the timeout", elapsed)
+	}
+	if report.Ready || report.Components["database"].Status != StatusDown {
+		t.Errorf("report = %+v, want the hanging check down", report)
+	}
+}
+
+func TestCheckerDraining(t *testing.T) {
+	checker := NewChecker(time.Second, staticCheck("database", true, "", nil))
+	if report := checker.Check(context.Background()); !report.Ready {
+		t.Fatalf("report before draining = %+v, want ready", report)
+	}
+
+	checker.SetDraining()
+	report := checker.Check(context.Background())
+	if report.Ready || !report.Draining {
+		t.Errorf("report while draining = %+v, want draining and not ready", report)
+	}
+	// The components are still checked and reported.
+	if report.Components["database"].Status != StatusUp {
+		t.Errorf("database while draining = %+v", report.Components["database"])
+	}
+}
@@ -0,0 +1,76 @@
+package infoapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	steps := []struct {
+		name      string
+		do        func()
+		advance   time.Duration
+		wantState State
+		wantAllow bool
+	}{
+		{name: "closed", wantState: StateClosed, wantAllow: true},
+		{name: "one failure", do: b.Failure, wantState: StateClosed, wantAllow: true},
+		{name: "success resets the count", do: b.Success, wantState: StateClosed, wantAllow: true},
+		{name: "failure after reset", do: b.Failure, wantState: StateClosed, wantAllow: true},
+		{name: "threshold reached", do: b.Failure, wantState: StateOpen, wantAllow: false},
+		{name: "cooling down", advance: 59 * time.Second, wantState: StateOpen, wantAllow: false},
+		{name: "cooldown over", advance: time.Second, wantState: StateHalfOpen, wantAllow: true},
+		{name: "trial failed", do: b.Failure, wantState: StateOpen, wantAllow: false},
+		{name: "cooldown over again", advance: time.Minute, wantState: StateHalfOpen, wantAllow: true},
+		{name: "trial succeeded", do: b.Success, wantState: StateClosed, wantAllow: true},
+	}
+	for _, step := range steps {
+		if step.do != nil {
+			step.do()
+		}
+		now = now.Add(step.advance)
+		if got := b.State(); got != step.wantState {
+			t.Fatalf("%s: state = %s, want %s", step.name, got, step.wantState)
+		}
+		err := b.Allow()
+		if (err == nil) != step.wantAllow {
+			t.Fatalf("%s: Allow() = %v, want allowed %t", step.name, err, step.wantAllow)
+		}
+		if err == nil && step.wantState == StateHalfOpen {
+			// Only one trial call goes through at a time.
+			if err := b.Allow(); err != ErrCircuitOpen {
+				t.Fatalf("%s: second trial Allow() = %v, want ErrCircuitOpen", step.name, err)
+			}
+		}
+	}
+}
+
+func TestBreakerRelease(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial Allow() = %v", err)
+	}
+	b.Release()
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow() after Release = %v, want another trial", err)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := NewBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		b.Failure()
+	}
+	if err := b.Allow(); err != nil || b.State() != StateClosed {
+		t.Errorf("disabled breaker: Allow() = %v, state %s", err, b.State())
+	}
+}
@@ -0,0 +1,208 @@
+package infoapi
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/metrics"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/requestlog"
+	"effectiveMobileTask/internal/tracing"
+	"effectiveMobileTask/lib/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var ErrNotFound = errors.New("song not found in info api")
+
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("info api responded with status code %d", e.StatusCode)
+}
+
+// Client calls the external music info service. Every attempt gets its own
+// timeout derived from the caller's context, 5xx responses and network errors
+// are retried with exponential backoff and jitter, and a circuit breaker
+// fails fast while the upstream keeps failing.
+type Client struct {
+	baseURL    string
+	infoPath   string
+	httpClient *http.Client
+	breaker    *Breaker
+
+	timeout        time.Duration
+	maxRetries     int
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+}
+
+func NewClient(cfg config.ExternalAPIConfig) *Client {
+	return &Client{
+		baseURL:        cfg.BaseURL,
+		infoPath:       cfg.InfoURL,
+		httpClient:     &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		breaker:        NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		timeout:        cfg.Timeout,
+		maxRetries:     cfg.MaxRetries,
+		backoffInitial: cfg.BackoffInitial,
+		backoffMax:     cfg.BackoffMax,
+	}
+}
+
+func (c *Client) BreakerState() State {
+	return c.breaker.State()
+}
+
+// GetSongDetail runs under its own span, so that retries and backoff show up
+// as children of one lookup; every attempt is traced by the HTTP transport.
+func (c *Client) GetSongDetail(ctx context.Context, group, song string) (detail models.SongDetail, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "infoapi.GetSongDetail",
+		trace.WithAttributes(attribute.String("song.group", group), attribute.String("song.title", song)))
+	defer func() {
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt)
+			logger.FromContext(ctx).Info("retrying info api request",
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay),
+				slog.Any("error", lastErr))
+
+			if err := sleep(ctx, delay); err != nil {
+				return models.SongDetail{}, err
+			}
+		}
+
+		if err := c.breaker.Allow(); err != nil {
+			metrics.UpstreamRejected()
+			return models.SongDetail{}, err
+		}
+
+		span.SetAttributes(attribute.Int("infoapi.attempts", attempt+1))
+		start := time.Now()
+		detail, err := c.do(ctx, group, song)
+		metrics.ObserveUpstream(outcome(err), time.Since(start))
+		switch {
+		case err == nil, errors.Is(err, ErrNotFound), isClientError(err):
+			c.breaker.Success()
+			return detail, err
+		case ctx.Err() != nil:
+			c.breaker.Release()
+			return models.SongDetail{}, ctx.Err()
+		}
+
+		c.breaker.Failure()
+		lastErr = err
+	}
+
+	return models.SongDetail{}, lastErr
+}
+
+func (c *Client) do(ctx context.Context, group, song string) (models.SongDetail, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	urlAPI := fmt.Sprintf("%s%s?group=%s&song=%s",
+		c.baseURL,
+		c.infoPath,
+		url.QueryEscape(group),
+		url.QueryEscape(song))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlAPI, nil)
+	if err != nil {
+		return models.SongDetail{}, fmt.Errorf("build song detail request: %w", err)
+	}
+	if id := requestlog.ID(ctx); id != "" {
+		req.Header.Set(requestlog.Header, id)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return models.SongDetail{}, fmt.Errorf("get song detail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return models.SongDetail{}, ErrNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return models.SongDetail{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	var detail models.SongDetail
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return models.SongDetail{}, fmt.Errorf("decode song detail: %w", err)
+	}
+
+	return detail, nil
+}
+
+// backoff returns the delay before the given retry attempt using "full
+// jitter": a random duration between zero and the capped exponential step.
+func (c *Client) backoff(attempt int) time.Duration {
+	step := c.backoffInitial << (attempt - 1)
+	if step <= 0 || (c.backoffMax > 0 && step > c.backoffMax) {
+		step = c.backoffMax
+	}
+	if step <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(step)) + 1)
+}
+
+// outcome classifies a request for the upstream metrics.
+func outcome(err error) string {
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		return metrics.UpstreamOK
+	case errors.Is(err, ErrNotFound):
+		return metrics.UpstreamNotFound
+	case errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError:
+		return metrics.UpstreamClientError
+	case errors.As(err, &statusErr):
+		return metrics.UpstreamServerError
+	}
+	return metrics.UpstreamError
+}
+
+func isClientError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
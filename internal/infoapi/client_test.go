@@ -0,0 +1,129 @@
+package infoapi
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetSongDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		// statuses are answered in turn, the last one from then on.
+		statuses     []int
+		wantErr      error
+		wantStatus   int
+		wantRequests int32
+	}{
+		{name: "ok", statuses: []int{http.StatusOK}, wantRequests: 1},
+		{name: "server errors are retried", statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, wantRequests: 3},
+		{name: "retries run out", statuses: []int{http.StatusInternalServerError}, wantStatus: http.StatusInternalServerError, wantRequests: 3},
+		{name: "not found is not retried", statuses: []int{http.StatusNotFound}, wantErr: ErrNotFound, wantRequests: 1},
+		{name: "client errors are not retried", statuses: []int{http.StatusBadRequest}, wantStatus: http.StatusBadRequest, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1))
+				status := tt.statuses[min(n, len(tt.statuses))-1]
+				if r.URL.Query().Get("group") != "Muse & Co" {
+					t.Errorf("group = %q", r.URL.Query().Get("group"))
+				}
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"release_date": "07.09.2009", "link": "https://example.com"}`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(config.ExternalAPIConfig{
+				BaseURL:        server.URL,
+				InfoURL:        "/info",
+				Timeout:        time.Second,
+				MaxRetries:     2,
+				BackoffInitial: time.Millisecond,
+				BackoffMax:     time.Millisecond,
+			})
+			detail, err := client.GetSongDetail(context.Background(), "Muse & Co", "Uprising")
+
+			var statusErr *StatusError
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantStatus != 0:
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus {
+					t.Errorf("error = %v, want status %d", err, tt.wantStatus)
+				}
+			case err != nil:
+				t.Errorf("error = %v", err)
+			case detail.Link != "https://example.com":
+				t.Errorf("detail = %+v", detail)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestGetSongDetailOpensBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(config.ExternalAPIConfig{
+		BaseURL:          server.URL,
+		Timeout:          time.Second,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetSongDetail(context.Background(), "Muse", "Uprising"); err == nil {
+			t.Fatal("GetSongDetail succeeded against a failing server")
+		}
+	}
+	if _, err := client.GetSongDetail(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error = %v, want ErrCircuitOpen", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+	if client.BreakerState() != StateOpen {
+		t.Errorf("breaker state = %s, want open", client.BreakerState())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	client := &Client{backoffInitial: 100 * time.Millisecond, backoffMax: time.Second}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{70, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if delay := client.backoff(tt.attempt); delay <= 0 || delay > tt.max {
+				t.Fatalf("backoff(%d) = %s, want within (0, %s]", tt.attempt, delay, tt.max)
+			}
+		}
+	}
+	if delay := (&Client{}).backoff(1); delay != 0 {
+		t.Errorf("backoff without configuration = %s, want 0", delay)
+	}
+}
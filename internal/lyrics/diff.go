@@ -0,0 +1,102 @@
+package lyrics
+
+import (
+	"strings"
+)
+
+const (
+	DiffEqual  = "="
+	DiffDelete = "-"
+	DiffInsert = "+"
+)
+
+// DiffLine is a line of a text comparison: kept, removed from the first
+// text or added in the second one.
+type DiffLine struct {
+	Op   string `json:"op" enums:"=,-,+" example:"+"`
+	Text string `json:"text" example:"Ooh baby, don't you know I suffer?"`
+}
+
+// maxDiffCells bounds the table Diff builds, so that two large texts
+// cannot exhaust the memory of the server: 4 MiB of int32 cells.
+const maxDiffCells = 1 << 20
+
+// Diff compares two texts line by line and returns the shortest edit
+// turning before into after, with removed lines ahead of added ones. Lines
+// the texts start and end with in common are kept as they are; when the
+// lines between are too many to compare, Diff returns false.
+func Diff(before, after string) ([]DiffLine, bool) {
+	a, b := splitLines(before), splitLines(after)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	if (len(a)-prefix-suffix+1)*(len(b)-prefix-suffix+1) > maxDiffCells {
+		return nil, false
+	}
+
+	diff := make([]DiffLine, 0, max(len(a), len(b)))
+	for _, line := range a[:prefix] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	diff = appendEdit(diff, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return diff, true
+}
+
+// appendEdit appends the shortest edit turning a into b to diff.
+func appendEdit(diff []DiffLine, a, b []string) []DiffLine {
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int32, len(a)+1)
+	cells := make([]int32, (len(a)+1)*(len(b)+1))
+	for i := range common {
+		common[i] = cells[i*(len(b)+1) : (i+1)*(len(b)+1)]
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,98 @@
+package lyrics
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []DiffLine
+	}{
+		{
+			name: "both empty",
+			want: []DiffLine{},
+		},
+		{
+			name:   "equal",
+			before: "a\nb",
+			after:  "a\nb",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name:  "added to empty",
+			after: "a\nb",
+			want:  []DiffLine{{DiffInsert, "a"}, {DiffInsert, "b"}},
+		},
+		{
+			name:   "removed all",
+			before: "a\nb",
+			want:   []DiffLine{{DiffDelete, "a"}, {DiffDelete, "b"}},
+		},
+		{
+			name:   "changed line in the middle",
+			before: "a\nb\nc",
+			after:  "a\nx\nc",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "x"}, {DiffEqual, "c"}},
+		},
+		{
+			name:   "inserted and removed lines",
+			before: "a\nb\nc\nd",
+			after:  "b\nc\ne\nd",
+			want:   []DiffLine{{DiffDelete, "a"}, {DiffEqual, "b"}, {DiffEqual, "c"}, {DiffInsert, "e"}, {DiffEqual, "d"}},
+		},
+		{
+			name:   "repeated lines",
+			before: "la\nla\nla",
+			after:  "la\nla",
+			want:   []DiffLine{{DiffEqual, "la"}, {DiffEqual, "la"}, {DiffDelete, "la"}},
+		},
+		{
+			name:   "windows line endings",
+			before: "a\r\nb",
+			after:  "a\nc",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "c"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Diff(tt.before, tt.after)
+			if !ok {
+				t.Fatal("Diff gave up")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffTooLarge(t *testing.T) {
+	lines := func(prefix string, n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteString(prefix + strconv.Itoa(i) + "\n")
+		}
+		return b.String()
+	}
+
+	if _, ok := Diff(lines("a", 2000), lines("b", 2000)); ok {
+		t.Error("Diff compared two texts of 2000 different lines")
+	}
+
+	// Lines in common at either end are not compared, so a small change
+	// to a large text is still diffed.
+	before := lines("a", 5000) + "old\n" + lines("c", 5000)
+	after := lines("a", 5000) + "new\n" + lines("c", 5000)
+	got, ok := Diff(before, after)
+	if !ok {
+		t.Fatal("Diff gave up on a one-line change")
+	}
+	if got[5000] != (DiffLine{DiffDelete, "old"}) || got[5001] != (DiffLine{DiffInsert, "new"}) {
+		t.Errorf("Diff = %v .. %v, want the changed line at 5000", got[5000], got[5001])
+	}
+}
@@ -0,0 +1,182 @@
+package lyrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LRC tags written before the other ones, in this order.
+var lrcTagOrder = []string{"ti", "ar", "al", "au", "by", "length", "offset", "re", "ve"}
+
+var (
+	lrcTimestamp = regexp.MustCompile(`^\[(\d{1,3}):(\d{2})(?:[.:](\d{1,3}))?\]`)
+	lrcTag       = regexp.MustCompile(`^\[([A-Za-z#]+):([^\]]*)\]$`)
+)
+
+// TimedLine is a line of synced lyrics shown from Time on. Lines with empty
+// Text mark a pause.
+type TimedLine struct {
+	Time time.Duration
+	Text string
+}
+
+// LRC is the content of an LRC file.
+type LRC struct {
+	// Tags holds metadata such as ar (artist) or ti (title), keyed by the
+	// lower-cased tag name.
+	Tags  map[string]string
+	Lines []TimedLine
+}
+
+// LRCError reports an invalid line of an LRC file.
+type LRCError struct {
+	Line    int
+	Message string
+}
+
+func (e *LRCError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseLRC reads an LRC file. Every line of lyrics must start with at least
+// one [mm:ss.xx] timestamp, and lines must come in chronological order. A
+// line with several timestamps is repeated at each of them.
+func ParseLRC(r io.Reader) (*LRC, error) {
+	lrc := &LRC{Tags: make(map[string]string)}
+
+	scanner := bufio.NewScanner(r)
+	number := 0
+	var previous time.Duration
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if number == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" {
+			continue
+		}
+
+		times, text, err := parseTimestamps(line)
+		if err != nil {
+			return nil, &LRCError{Line: number, Message: err.Error()}
+		}
+
+		if len(times) == 0 {
+			match := lrcTag.FindStringSubmatch(line)
+			if match == nil {
+				return nil, &LRCError{Line: number, Message: "expected a [mm:ss.xx] timestamp or a [tag:value] line"}
+			}
+			lrc.Tags[strings.ToLower(match[1])] = strings.TrimSpace(match[2])
+			continue
+		}
+
+		if times[0] < previous {
+			return nil, &LRCError{Line: number, Message: fmt.Sprintf("timestamp %s is earlier than the previous line", FormatTimestamp(times[0]))}
+		}
+		previous = times[0]
+
+		for _, t := range times {
+			lrc.Lines = append(lrc.Lines, TimedLine{Time: t, Text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(lrc.Lines) == 0 {
+		return nil, &LRCError{Message: "no timed lines found"}
+	}
+
+	sort.SliceStable(lrc.Lines, func(i, j int) bool { return lrc.Lines[i].Time < lrc.Lines[j].Time })
+	return lrc, nil
+}
+
+// parseTimestamps strips the leading timestamps off the line.
+func parseTimestamps(line string) ([]time.Duration, string, error) {
+	times := make([]time.Duration, 0, 1)
+	for {
+		match := lrcTimestamp.FindStringSubmatch(line)
+		if match == nil {
+			return times, strings.TrimSpace(line), nil
+		}
+
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		if seconds >= 60 {
+			return nil, "", fmt.Errorf("invalid timestamp %s", match[0])
+		}
+		// The fraction is in hundredths, but tenths and milliseconds are
+		// common too: "5" is 500ms, "12" is 120ms and "123" is 123ms.
+		fraction := 0
+		if match[3] != "" {
+			fraction, _ = strconv.Atoi((match[3] + "00")[:3])
+		}
+
+		times = append(times, time.Duration(minutes)*time.Minute+
+			time.Duration(seconds)*time.Second+
+			time.Duration(fraction)*time.Millisecond)
+		line = line[len(match[0]):]
+	}
+}
+
+// Format writes the LRC file, tags first.
+func (l *LRC) Format(w io.Writer) error {
+	keys := make([]string, 0, len(l.Tags))
+	for key := range l.Tags {
+		if !slices.Contains(lrcTagOrder, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range append(slices.Clone(lrcTagOrder), keys...) {
+		if value, ok := l.Tags[key]; ok {
+			fmt.Fprintf(&b, "[%s:%s]\n", key, value)
+		}
+	}
+	for _, line := range l.Lines {
+		fmt.Fprintf(&b, "[%s]%s\n", FormatTimestamp(line.Time), line.Text)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FormatTimestamp formats the time as mm:ss.xx.
+func FormatTimestamp(t time.Duration) string {
+	centiseconds := t.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", centiseconds/6000, centiseconds/100%60, centiseconds%100)
+}
+
+// PlainText returns the lyrics without timings. Pauses become blank lines
+// between sections.
+func (l *LRC) PlainText() string {
+	sections := make([]string, 0)
+	current := make([]string, 0)
+	for _, line := range l.Lines {
+		if line.Text == "" {
+			if len(current) > 0 {
+				sections = append(sections, strings.Join(current, "\n"))
+				current = current[:0]
+			}
+			continue
+		}
+		current = append(current, line.Text)
+	}
+	if len(current) > 0 {
+		sections = append(sections, strings.Join(current, "\n"))
+	}
+	return strings.Join(sections, sectionBreak)
+}
@@ -0,0 +1,131 @@
+package lyrics
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantTags map[string]string
+		want     []TimedLine
+	}{
+		{
+			name:     "tags and lines",
+			input:    "[ti:Uprising]\n[AR: Muse]\n[00:01.50]Paranoia is in bloom\n[00:05.2]The PR transmissions will resume\n",
+			wantTags: map[string]string{"ti": "Uprising", "ar": "Muse"},
+			want: []TimedLine{
+				{1500 * time.Millisecond, "Paranoia is in bloom"},
+				{5200 * time.Millisecond, "The PR transmissions will resume"},
+			},
+		},
+		{
+			name:     "fractions in tenths, hundredths and milliseconds",
+			input:    "[00:01.5]a\n[00:02.12]b\n[00:03.123]c\n[00:04]d",
+			wantTags: map[string]string{},
+			want: []TimedLine{
+				{1500 * time.Millisecond, "a"},
+				{2120 * time.Millisecond, "b"},
+				{3123 * time.Millisecond, "c"},
+				{4 * time.Second, "d"},
+			},
+		},
+		{
+			name:     "repeated line",
+			input:    "[00:01.00][00:09.00]They will not force us\n[00:05.00]And they will stop degrading us",
+			wantTags: map[string]string{},
+			want: []TimedLine{
+				{time.Second, "They will not force us"},
+				{5 * time.Second, "And they will stop degrading us"},
+				{9 * time.Second, "They will not force us"},
+			},
+		},
+		{
+			name:     "byte order mark, blank lines and pauses",
+			input:    "\uFEFF[00:01.00]a\r\n\r\n[00:02.00]\r\n[01:02.00]b",
+			wantTags: map[string]string{},
+			want: []TimedLine{
+				{time.Second, "a"},
+				{2 * time.Second, ""},
+				{62 * time.Second, "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lrc, err := ParseLRC(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseLRC: %v", err)
+			}
+			if !reflect.DeepEqual(lrc.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", lrc.Tags, tt.wantTags)
+			}
+			if !reflect.DeepEqual(lrc.Lines, tt.want) {
+				t.Errorf("lines = %v, want %v", lrc.Lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLRCErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantLine int
+	}{
+		{"untimed line", "[00:01.00]a\nplain text", 2},
+		{"seconds out of range", "[00:61.00]a", 1},
+		{"out of order", "[00:05.00]a\n[00:01.00]b", 2},
+		{"only tags", "[ti:Uprising]", 0},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLRC(strings.NewReader(tt.input))
+			var lrcErr *LRCError
+			if !errors.As(err, &lrcErr) {
+				t.Fatalf("ParseLRC error = %v, want an LRCError", err)
+			}
+			if lrcErr.Line != tt.wantLine {
+				t.Errorf("error on line %d, want %d: %v", lrcErr.Line, tt.wantLine, err)
+			}
+		})
+	}
+}
+
+func TestLRCFormat(t *testing.T) {
+	lrc := &LRC{
+		Tags: map[string]string{"x-custom": "1", "ar": "Muse", "ti": "Uprising"},
+		Lines: []TimedLine{
+			{1500 * time.Millisecond, "a"},
+			{61*time.Second + 5*time.Millisecond, "b"},
+		},
+	}
+	var b strings.Builder
+	if err := lrc.Format(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "[ti:Uprising]\n[ar:Muse]\n[x-custom:1]\n[00:01.50]a\n[01:01.00]b\n"
+	if b.String() != want {
+		t.Errorf("Format = %q, want %q", b.String(), want)
+	}
+}
+
+func TestLRCPlainText(t *testing.T) {
+	lrc := &LRC{Lines: []TimedLine{
+		{0, ""},
+		{time.Second, "a"},
+		{2 * time.Second, "b"},
+		{3 * time.Second, ""},
+		{4 * time.Second, ""},
+		{5 * time.Second, "c"},
+	}}
+	if got, want := lrc.PlainText(), "a\nb\n\nc"; got != want {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+// Package lyrics splits song texts into sections such as verses and
+// choruses.
+package lyrics
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Section types.
+const (
+	TypeIntro     = "intro"
+	TypeVerse     = "verse"
+	TypePreChorus = "pre-chorus"
+	TypeChorus    = "chorus"
+	TypeBridge    = "bridge"
+	TypeOutro     = "outro"
+	TypeOther     = "other"
+)
+
+// Sections are separated by a blank line.
+const sectionBreak = "\n\n"
+
+// headerTypes maps the words used in section headers to section types.
+var headerTypes = map[string]string{
+	"intro":        TypeIntro,
+	"вступление":   TypeIntro,
+	"verse":        TypeVerse,
+	"куплет":       TypeVerse,
+	"pre-chorus":   TypePreChorus,
+	"prechorus":    TypePreChorus,
+	"пре-припев":   TypePreChorus,
+	"предприпев":   TypePreChorus,
+	"chorus":       TypeChorus,
+	"refrain":      TypeChorus,
+	"hook":         TypeChorus,
+	"припев":       TypeChorus,
+	"bridge":       TypeBridge,
+	"бридж":        TypeBridge,
+	"outro":        TypeOutro,
+	"концовка":     TypeOutro,
+	"заключение":   TypeOutro,
+	"instrumental": TypeOther,
+	"solo":         TypeOther,
+}
+
+// headerPattern matches lines like "[Chorus]", "[Verse 2]", "Verse 2:" or
+// "Припев:". Bracketed headers may contain anything, the others must start
+// with a known word.
+var headerPattern = regexp.MustCompile(`^\[([^\]]+)\]$|^([\p{L}-]+)(?:\s+(\d+))?:$`)
+
+// Section is a block of lines separated from its neighbours by a blank line.
+type Section struct {
+	Type string
+	// Ordinal counts sections of the same type, starting at 1: the second
+	// verse has Type "verse" and Ordinal 2.
+	Ordinal int
+	// Header is the header line as written in the text, if there was one.
+	Header string
+	Lines  []string
+	// FirstLine is the number of the first line within the whole song,
+	// starting at 1. Headers are not counted as lines.
+	FirstLine int
+}
+
+// LastLine is the number of the last line of the section, or FirstLine-1
+// for a section without lines.
+func (s Section) LastLine() int {
+	return s.FirstLine + len(s.Lines) - 1
+}
+
+// Text returns the section as it is written in the song text.
+func (s Section) Text() string {
+	if s.Header == "" {
+		return strings.Join(s.Lines, "\n")
+	}
+	return strings.Join(append([]string{s.Header}, s.Lines...), "\n")
+}
+
+// Parse splits the text into sections on blank lines. Sections starting
+// with a header line take their type from it. Other sections are choruses
+// when the same lines occur more than once in the song, and verses
+// otherwise.
+func Parse(text string) []Section {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	blocks := make([][]string, 0)
+	for _, block := range strings.Split(text, sectionBreak) {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		blocks = append(blocks, lines)
+	}
+
+	sections := make([]Section, len(blocks))
+	repeats := make(map[string]int)
+	for i, lines := range blocks {
+		section := Section{Type: TypeVerse, Lines: lines}
+		if sectionType, ok := headerType(lines[0]); ok {
+			section.Type = sectionType
+			section.Header = lines[0]
+			section.Lines = lines[1:]
+		}
+		sections[i] = section
+		repeats[strings.Join(section.Lines, "\n")]++
+	}
+
+	ordinals := make(map[string]int)
+	nextLine := 1
+	for i := range sections {
+		section := &sections[i]
+		if section.Header == "" && len(section.Lines) > 0 && repeats[strings.Join(section.Lines, "\n")] > 1 {
+			section.Type = TypeChorus
+		}
+		ordinals[section.Type]++
+		section.Ordinal = ordinals[section.Type]
+		section.FirstLine = nextLine
+		nextLine += len(section.Lines)
+	}
+	return sections
+}
+
+// Join puts the sections back together into a song text.
+func Join(sections []Section) string {
+	texts := make([]string, len(sections))
+	for i, section := range sections {
+		texts[i] = section.Text()
+	}
+	return strings.Join(texts, sectionBreak)
+}
+
+// Lines returns all lines of the song, headers excluded, so that line n is
+// at index n-1.
+func Lines(sections []Section) []string {
+	lines := make([]string, 0)
+	for _, section := range sections {
+		lines = append(lines, section.Lines...)
+	}
+	return lines
+}
+
+func headerType(line string) (string, bool) {
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return "", false
+	}
+
+	if match[1] != "" {
+		// "[Verse 2: Artist]" -> "verse"
+		name := strings.ToLower(strings.TrimSpace(match[1]))
+		name = strings.TrimSpace(strings.SplitN(name, ":", 2)[0])
+		name = strings.TrimRightFunc(name, func(r rune) bool { return r == ' ' || (r >= '0' && r <= '9') })
+		if sectionType, ok := headerTypes[name]; ok {
+			return sectionType, true
+		}
+		return TypeOther, true
+	}
+
+	sectionType, ok := headerTypes[strings.ToLower(match[2])]
+	return sectionType, ok
+}
+
+// SectionID identifies a section within a song, e.g. "verse-2".
+func SectionID(sectionType string, ordinal int) string {
+	return sectionType + "-" + strconv.Itoa(ordinal)
+}
+
+// ParseSectionID parses "verse-3" or "chorus" (the first chorus) into a
+// section type and ordinal.
+func ParseSectionID(id string) (string, int, bool) {
+	sectionType, ordinal := id, 1
+	if i := strings.LastIndex(id, "-"); i > 0 {
+		if n, err := strconv.Atoi(id[i+1:]); err == nil {
+			sectionType, ordinal = id[:i], n
+		}
+	}
+
+	switch sectionType {
+	case TypeIntro, TypeVerse, TypePreChorus, TypeChorus, TypeBridge, TypeOutro, TypeOther:
+	default:
+		return "", 0, false
+	}
+	return sectionType, ordinal, ordinal >= 1
+}
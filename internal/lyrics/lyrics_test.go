@@ -0,0 +1,95 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Section
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: []Section{},
+		},
+		{
+			name: "verses and repeated chorus",
+			text: "a\nb\n\nc\nc\n\nd\n\nc\nc",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"a", "b"}, FirstLine: 1},
+				{Type: TypeChorus, Ordinal: 1, Lines: []string{"c", "c"}, FirstLine: 3},
+				{Type: TypeVerse, Ordinal: 2, Lines: []string{"d"}, FirstLine: 5},
+				{Type: TypeChorus, Ordinal: 2, Lines: []string{"c", "c"}, FirstLine: 6},
+			},
+		},
+		{
+			name: "headers",
+			text: "[Verse 1: Artist]\na\n\nПрипев:\nb\n\n[Guitar]\nc\n\nBridge 2:\nd",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Header: "[Verse 1: Artist]", Lines: []string{"a"}, FirstLine: 1},
+				{Type: TypeChorus, Ordinal: 1, Header: "Припев:", Lines: []string{"b"}, FirstLine: 2},
+				{Type: TypeOther, Ordinal: 1, Header: "[Guitar]", Lines: []string{"c"}, FirstLine: 3},
+				{Type: TypeBridge, Ordinal: 1, Header: "Bridge 2:", Lines: []string{"d"}, FirstLine: 4},
+			},
+		},
+		{
+			name: "unknown word is not a header",
+			text: "Note:\na",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"Note:", "a"}, FirstLine: 1},
+			},
+		},
+		{
+			name: "windows line endings, extra blank lines and trailing spaces",
+			text: "a  \r\n\r\n\r\n\r\nb\t\r\n",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"a"}, FirstLine: 1},
+				{Type: TypeVerse, Ordinal: 2, Lines: []string{"b"}, FirstLine: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	text := "[Chorus]\na\nb\n\nc"
+	if got := Join(Parse(text)); got != text {
+		t.Errorf("Join(Parse(%q)) = %q", text, got)
+	}
+}
+
+func TestParseSectionID(t *testing.T) {
+	tests := []struct {
+		id          string
+		wantType    string
+		wantOrdinal int
+		wantOK      bool
+	}{
+		{"verse-2", TypeVerse, 2, true},
+		{"chorus", TypeChorus, 1, true},
+		{"pre-chorus", TypePreChorus, 1, true},
+		{"pre-chorus-3", TypePreChorus, 3, true},
+		{"verse-0", TypeVerse, 0, false},
+		{"solo-1", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		sectionType, ordinal, ok := ParseSectionID(tt.id)
+		if sectionType != tt.wantType || ordinal != tt.wantOrdinal || ok != tt.wantOK {
+			t.Errorf("ParseSectionID(%q) = %q, %d, %t, want %q, %d, %t", tt.id, sectionType, ordinal, ok, tt.wantType, tt.wantOrdinal, tt.wantOK)
+		}
+		if ok && SectionID(sectionType, ordinal) != tt.id && ordinal != 1 {
+			t.Errorf("SectionID(%q, %d) = %q, want %q", sectionType, ordinal, SectionID(sectionType, ordinal), tt.id)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Outcomes of requests to the enrichment upstream.
+const (
+	UpstreamOK          = "ok"
+	UpstreamNotFound    = "not_found"
+	UpstreamClientError = "client_error"
+	UpstreamServerError = "server_error"
+	UpstreamError       = "error"
+)
+
+var (
+	upstreamDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Duration of requests to the info API by outcome; every retry is a request.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"outcome"})
+
+	upstreamRejected = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "upstream_rejected_total",
+		Help:      "Requests to the info API not sent because the circuit was open.",
+	})
+)
+
+// ObserveUpstream records a request to the info API.
+func ObserveUpstream(outcome string, duration time.Duration) {
+	upstreamDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// UpstreamRejected counts a request the circuit breaker did not let
+// through.
+func UpstreamRejected() {
+	upstreamRejected.Inc()
+}
+
+// RegisterCircuitState exports the state of the info API circuit breaker.
+func RegisterCircuitState(state func() float64) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "upstream_circuit_state",
+		Help:      "State of the info API circuit breaker: 0 closed, 1 open, 2 half-open.",
+	}, state)
+}
+
+// RegisterCache exports the hits and misses of the enrichment cache; the
+// hit rate is hits over hits plus misses.
+func RegisterCache(stats func() (hits, misses uint64)) {
+	for _, result := range []string{"hit", "miss"} {
+		factory.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "enrichment",
+			Name:        "cache_lookups_total",
+			Help:        "Enrichment lookups answered from the cache (hit) or passed on to the providers (miss).",
+			ConstLabels: prometheus.Labels{"result": result},
+		}, func() float64 {
+			hits, misses := stats()
+			if result == "hit" {
+				return float64(hits)
+			}
+			return float64(misses)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"log/slog"
+	"time"
+)
+
+// RegisterDB exports the connection pool statistics of the database as
+// go_sql_* metrics.
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterCatalog exports the number of songs and groups, counted on every
+// scrape.
+func RegisterCatalog(songs repository.SongRepository, groups repository.GroupRepository) {
+	Registry.MustRegister(&catalogCollector{songs: songs, groups: groups})
+}
+
+// catalogTimeout bounds the count queries of a scrape.
+const catalogTimeout = 2 * time.Second
+
+var (
+	songsDesc  = prometheus.NewDesc(prometheus.BuildFQName(namespace, "catalog", "songs"), "Number of songs, not counting deleted ones.", nil, nil)
+	groupsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "catalog", "groups"), "Number of groups, not counting deleted ones.", nil, nil)
+)
+
+type catalogCollector struct {
+	songs  repository.SongRepository
+	groups repository.GroupRepository
+}
+
+func (c *catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- songsDesc
+	ch <- groupsDesc
+}
+
+// Collect leaves a metric out when its query fails, rather than failing
+// the whole scrape.
+func (c *catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+
+	if count, err := c.songs.Count(ctx, repository.SongFilter{}); err == nil {
+		ch <- prometheus.MustNewConstMetric(songsDesc, prometheus.GaugeValue, float64(count))
+	} else {
+		logger.Error("failed to count songs for metrics", slog.Any("error", err))
+	}
+
+	if count, err := c.groups.Count(ctx, repository.GroupFilter{}); err == nil {
+		ch <- prometheus.MustNewConstMetric(groupsDesc, prometheus.GaugeValue, float64(count))
+	} else {
+		logger.Error("failed to count groups for metrics", slog.Any("error", err))
+	}
+}
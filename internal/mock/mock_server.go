@@ -2,16 +2,28 @@ package mock
 
 import (
 	"effectiveMobileTask/config"
-	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/requestlog"
+	"effectiveMobileTask/internal/tracing"
 	"effectiveMobileTask/lib/logger"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"log"
 	"log/slog"
 	"net/http"
 )
 
-func MockServer() {
-	testRouter := gin.Default()
+// NewServer returns the mock info API server, which answers from the
+// enrichment catalog on SERVER_MOCK_SERVER_PORT.
+func NewServer() (*http.Server, error) {
+	catalog, err := enrichment.NewCatalogEnricher(config.AppConfig.Enrichment.CatalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading mock server catalog: %w", err)
+	}
+
+	testRouter := gin.New()
+	testRouter.Use(tracing.Middleware(), requestlog.Middleware(), apperr.Middleware(), requestlog.Recovery())
+	testRouter.NoRoute(apperr.NoRoute)
 
 	testRouter.GET("/info", func(c *gin.Context) {
 		groupName := c.Query("group")
@@ -19,14 +31,21 @@ func MockServer() {
 
 		if groupName == "" || songTitle == "" {
 			logger.Debug("group or song is empty")
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing parameters"})
+			var fields []apperr.FieldError
+			if groupName == "" {
+				fields = append(fields, apperr.FieldError{Field: "group", Message: "is required"})
+			}
+			if songTitle == "" {
+				fields = append(fields, apperr.FieldError{Field: "song", Message: "is required"})
+			}
+			apperr.Abort(c, apperr.Validation("group and song are required", fields...))
 			return
 		}
 
-		songDetail, err := controllers.GetSongDetailJSON(groupName, songTitle)
+		songDetail, err := catalog.Enrich(c.Request.Context(), groupName, songTitle)
 		if err != nil {
-			logger.Debug("get song detail fail", err)
-			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			logger.Debug("get song detail fail", slog.Any("error", err))
+			apperr.Abort(c, apperr.NotFound("song not found"))
 			return
 		}
 
@@ -34,7 +53,11 @@ func MockServer() {
 		c.JSON(http.StatusOK, songDetail)
 	})
 
-	if err := testRouter.Run(":" + config.AppConfig.Server.MockServerPort); err != nil {
-		log.Fatal("error starting mock server", err)
-	}
+	return &http.Server{
+		Addr:         ":" + config.AppConfig.Server.MockServerPort,
+		Handler:      testRouter,
+		ReadTimeout:  config.AppConfig.Server.ReadTimeout,
+		WriteTimeout: config.AppConfig.Server.WriteTimeout,
+		IdleTimeout:  config.AppConfig.Server.IdleTimeout,
+	}, nil
 }
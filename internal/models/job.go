@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+type EnrichmentJob struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SongID    uint       `json:"song_id" gorm:"index"`
+	Group     string     `json:"group"`
+	Song      string     `json:"song"`
+	Status    string     `json:"status" gorm:"index:idx_enrichment_jobs_pickup,priority:1"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	RunAt     time.Time  `json:"run_at" gorm:"index:idx_enrichment_jobs_pickup,priority:2"`
+	LockedAt  *time.Time `json:"locked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
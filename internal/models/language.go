@@ -0,0 +1,42 @@
+package models
+
+// DefaultLanguage is the text search configuration used when a song does not
+// specify one. It does no stemming, so it works for any language.
+const DefaultLanguage = "simple"
+
+// languages lists the text search configurations shipped with Postgres.
+var languages = map[string]bool{
+	"simple":     true,
+	"arabic":     true,
+	"armenian":   true,
+	"basque":     true,
+	"catalan":    true,
+	"danish":     true,
+	"dutch":      true,
+	"english":    true,
+	"finnish":    true,
+	"french":     true,
+	"german":     true,
+	"greek":      true,
+	"hindi":      true,
+	"hungarian":  true,
+	"indonesian": true,
+	"irish":      true,
+	"italian":    true,
+	"lithuanian": true,
+	"nepali":     true,
+	"norwegian":  true,
+	"portuguese": true,
+	"romanian":   true,
+	"russian":    true,
+	"serbian":    true,
+	"spanish":    true,
+	"swedish":    true,
+	"tamil":      true,
+	"turkish":    true,
+	"yiddish":    true,
+}
+
+func IsSupportedLanguage(language string) bool {
+	return languages[language]
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// MaxNameLength bounds group names and song titles, in characters.
+const MaxNameLength = 255
+
+// ParseReleaseDate accepts the DD.MM.YYYY dates of the info API as well as
+// ISO YYYY-MM-DD ones.
+func ParseReleaseDate(value string) (time.Time, error) {
+	date, err := time.Parse("02.01.2006", value)
+	if err == nil {
+		return date, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
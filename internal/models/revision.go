@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	RevisionEntitySong  = "song"
+	RevisionEntityGroup = "group"
+)
+
+const (
+	RevisionActionCreate  = "create"
+	RevisionActionUpdate  = "update"
+	RevisionActionDelete  = "delete"
+	RevisionActionRestore = "restore"
+	RevisionActionRevert  = "revert"
+	RevisionActionPurge   = "purge"
+)
+
+// Revision records one change of a song or group: who made it and when,
+// the fields it changed and the state of the record after the change.
+// Revisions are never updated or removed, not even when the record is
+// purged from the trash.
+type Revision struct {
+	ID         uint   `gorm:"primaryKey" json:"id" example:"12"`
+	EntityType string `json:"entity_type" example:"song"`
+	EntityID   uint   `json:"entity_id" example:"1"`
+	Action     string `json:"action" example:"update"`
+	Actor      string `json:"actor" example:"alice"`
+	// RevertOf is the revision whose state a revert restored.
+	RevertOf  *uint                  `json:"revert_of,omitempty" example:"7"`
+	Changes   map[string]FieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	State     map[string]string      `json:"state" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type FieldChange struct {
+	Before string `json:"before" example:"Supermassive Black Hole"`
+	After  string `json:"after" example:"Starlight"`
+}
+
+// RevisionState returns the fields of the song tracked by revisions. The
+// group is given by name, as GroupName is not always loaded; its ID is kept
+// as well, so that a revert finds the group even after a rename.
+func (s *Song) RevisionState(groupName string) map[string]string {
+	state := map[string]string{
+		"group_id":     strconv.FormatUint(uint64(s.GroupId), 10),
+		"group_name":   groupName,
+		"song":         s.Title,
+		"release_date": "",
+		"text":         s.Text,
+		"link":         s.Link,
+		"language":     s.Language,
+	}
+	if !s.ReleaseDate.IsZero() {
+		state["release_date"] = s.ReleaseDate.Format(time.DateOnly)
+	}
+	return state
+}
+
+// RevisionState returns the fields of the group tracked by revisions.
+func (g *Group) RevisionState() map[string]string {
+	return map[string]string{"name": g.Name}
+}
+
+// DiffStates returns the fields whose values differ between two states.
+// A field missing from one of them counts as empty.
+func DiffStates(before, after map[string]string) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for field, value := range after {
+		if before[field] != value {
+			changes[field] = FieldChange{Before: before[field], After: value}
+		}
+	}
+	for field, value := range before {
+		if _, ok := after[field]; !ok && value != "" {
+			changes[field] = FieldChange{Before: value}
+		}
+	}
+	return changes
+}
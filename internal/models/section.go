@@ -0,0 +1,15 @@
+package models
+
+// SongSection is a verse, chorus or other part of the song text. Sections
+// are derived from Song.Text whenever the song is written.
+type SongSection struct {
+	ID     uint `gorm:"primaryKey" json:"-"`
+	SongID uint `json:"-" gorm:"uniqueIndex:idx_song_sections_position,priority:1"`
+	// Position is the place of the section in the song, starting at 1.
+	Position  int      `json:"position" gorm:"uniqueIndex:idx_song_sections_position,priority:2"`
+	Type      string   `json:"type" example:"verse"`
+	Ordinal   int      `json:"ordinal" example:"2"`
+	Header    string   `json:"header,omitempty" example:"[Verse 2]"`
+	FirstLine int      `json:"first_line" example:"5"`
+	Lines     []string `json:"lines" gorm:"type:jsonb;serializer:json"`
+}
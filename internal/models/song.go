@@ -1,29 +1,39 @@
 package models
 
 import (
+	"gorm.io/gorm"
 	"time"
 )
 
 type Group struct {
-	ID        uint       `gorm:"primaryKey" json:"id"`
-	Name      string     `json:"name" gorm:"unique;index"`
-	Songs     []Song     `json:"songs,omitempty" gorm:"foreignKey:GroupId"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Name      string         `json:"name" gorm:"uniqueIndex:idx_groups_name,where:deleted_at IS NULL"`
+	Songs     []Song         `json:"songs,omitempty" gorm:"foreignKey:GroupId"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
 }
 
+const (
+	EnrichmentStatusPending  = "pending"
+	EnrichmentStatusEnriched = "enriched"
+	EnrichmentStatusFailed   = "failed"
+)
+
 type Song struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	GroupId     uint       `json:"group_id" gorm:"index"`
-	GroupName   string     `json:"group_name" gorm:"index"`
-	Title       string     `json:"song"`
-	ReleaseDate time.Time  `json:"release_date"`
-	Text        string     `json:"text"`
-	Link        string     `json:"link"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	GroupId          uint           `json:"group_id" gorm:"index"`
+	GroupName        string         `json:"group_name" gorm:"index"`
+	Title            string         `json:"song"`
+	ReleaseDate      time.Time      `json:"release_date"`
+	Text             string         `json:"text"`
+	Link             string         `json:"link"`
+	EnrichmentStatus string         `json:"enrichment_status" gorm:"not null;default:enriched"`
+	Language         string         `json:"language" gorm:"type:regconfig;not null;default:simple"`
+	Version          uint           `json:"version" gorm:"not null;default:1" example:"1"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
 }
 
 type SongDetail struct {
@@ -39,10 +49,15 @@ type AddNewSong struct {
 	Song  string `json:"song"`
 }
 
+// SongUpdate holds the fields of a PATCH request; nil fields are left as
+// they are. The binding tags are checked by the validation package.
 type SongUpdate struct {
-	GroupName   *string `json:"group_name"`
-	Song        *string `json:"song,omitempty"`
-	ReleaseDate *string `json:"release_date,omitempty"`
-	Text        *string `json:"text,omitempty"`
-	Link        *string `json:"link,omitempty"`
+	GroupName *string `json:"group_name" binding:"omitempty,notblank,max=255" minLength:"1" example:"Muse"`
+	Song      *string `json:"song,omitempty" binding:"omitempty,notblank,max=255" minLength:"1" example:"Supermassive Black Hole"`
+	// DD.MM.YYYY or YYYY-MM-DD
+	ReleaseDate *string `json:"release_date,omitempty" binding:"omitempty,releasedate" example:"16.07.2006"`
+	Text        *string `json:"text,omitempty" binding:"omitempty,notblank" minLength:"1" example:"Ooh baby, don't you know I suffer?"`
+	Link        *string `json:"link,omitempty" binding:"omitempty,httpurl" format:"uri" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	// Text search configuration, e.g. english or russian
+	Language *string `json:"language,omitempty" binding:"omitempty,language" example:"english"`
 }
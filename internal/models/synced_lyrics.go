@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// SyncedLyrics holds the line timings of a song uploaded as an LRC file.
+// Song.Text holds the same lines without timings.
+type SyncedLyrics struct {
+	SongID    uint              `gorm:"primaryKey;autoIncrement:false" json:"song_id"`
+	Tags      map[string]string `json:"tags" gorm:"type:jsonb;serializer:json"`
+	Lines     []TimedLine       `json:"lines" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func (SyncedLyrics) TableName() string {
+	return "synced_lyrics"
+}
+
+type TimedLine struct {
+	TimeMs int64  `json:"time_ms" example:"12340"`
+	Text   string `json:"text" example:"Ooh baby, don't you know I suffer?"`
+}
@@ -0,0 +1,132 @@
+// Package requestlog gives every request an ID and writes a structured
+// access log line once it is served.
+package requestlog
+
+import (
+	"context"
+	"crypto/rand"
+	"effectiveMobileTask/lib/logger"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Header carries the request ID. An ID sent by the client is kept, so that
+// a request can be followed across services; otherwise one is generated.
+const Header = "X-Request-ID"
+
+// maxIDLength bounds the IDs accepted from clients.
+const maxIDLength = 128
+
+// quietRoutes are polled by probes and scrapers; they are logged at debug
+// level so that they do not drown the rest of the access log.
+var quietRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+type idKey struct{}
+
+// ID returns the ID of the request ctx belongs to, or an empty string.
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// Middleware assigns the request ID, stores it together with the route and
+// the song or group ID in the request context for logger.FromContext, and
+// logs the request when it completes.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(Header)
+		if !validID(id) {
+			id = newID()
+		}
+		c.Header(Header, id)
+
+		route := c.FullPath()
+		attrs := []slog.Attr{slog.String("request_id", id)}
+		if route != "" {
+			attrs = append(attrs, slog.String("route", route))
+		}
+		if value := c.Param("id"); value != "" {
+			switch {
+			case strings.HasPrefix(route, "/songs/:id"):
+				attrs = append(attrs, slog.String("song_id", value))
+			case strings.HasPrefix(route, "/groups/:id"):
+				attrs = append(attrs, slog.String("group_id", value))
+			}
+		}
+		ctx := context.WithValue(c.Request.Context(), idKey{}, id)
+		c.Request = c.Request.WithContext(logger.WithAttrs(ctx, attrs...))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quietRoutes[route]:
+			level = slog.LevelDebug
+		}
+
+		fields := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, slog.String("errors", c.Errors.String()))
+		}
+		logger.FromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "request completed", fields...)
+	}
+}
+
+// Recovery logs a panic with the stack and the request attributes and
+// records it as the error of the request, which apperr.Middleware renders
+// as a 500 response.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		logger.FromContext(c.Request.Context()).Error("panic while serving request",
+			slog.Any("error", err),
+			slog.String("stack", string(debug.Stack())))
+		_ = c.Error(fmt.Errorf("panic: %v", err))
+		c.Abort()
+	})
+}
+
+// validID accepts IDs of printable ASCII without spaces, so that a client
+// cannot inject anything odd into the logs or response headers.
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,179 @@
+package requestlog
+
+import (
+	"bytes"
+	"effectiveMobileTask/lib/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// captureLogs makes the logger write JSON records of every level to the
+// returned buffer for the rest of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	previous := logger.Logger
+	t.Cleanup(func() { logger.Logger = previous })
+	var b bytes.Buffer
+	if err := logger.Setup("debug", logger.FormatJSON, &b); err != nil {
+		t.Fatal(err)
+	}
+	return &b
+}
+
+// records decodes the log records written to b.
+func records(t *testing.T, b *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log record %s: %v", line, err)
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+func newRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware())
+	return r
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	var seen string
+	r := newRouter()
+	r.GET("/songs/:id", func(c *gin.Context) { seen = ID(c.Request.Context()) })
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	tests := []struct {
+		name   string
+		header string
+		// kept tells whether the ID of the client is used.
+		kept bool
+	}{
+		{name: "given", header: "req-42", kept: true},
+		{name: "uuid", header: "3d8e1eb8-d812-7e84-c7af-8128309f68ef", kept: true},
+		{name: "missing", header: ""},
+		{name: "space", header: "req 42"},
+		{name: "control character", header: "req\x0142"},
+		{name: "non-ASCII", header: "запрос"},
+		{name: "too long", header: strings.Repeat("a", maxIDLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/songs/1", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get(Header)
+			if got != seen {
+				t.Errorf("response ID %q differs from the context one %q", got, seen)
+			}
+			if tt.kept && got != tt.header {
+				t.Errorf("ID = %q, want the client's %q", got, tt.header)
+			}
+			if !tt.kept && !generated.MatchString(got) {
+				t.Errorf("ID = %q, want a generated one", got)
+			}
+		})
+	}
+}
+
+func TestMiddlewareAccessLog(t *testing.T) {
+	logs := captureLogs(t)
+	r := newRouter()
+	r.GET("/songs/:id", func(c *gin.Context) {
+		status := http.StatusOK
+		switch c.Param("id") {
+		case "404":
+			status = http.StatusNotFound
+		case "500":
+			status = http.StatusInternalServerError
+		}
+		c.Status(status)
+	})
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		target string
+		level  string
+	}{
+		{"/songs/1", "INFO"},
+		{"/songs/404", "WARN"},
+		{"/songs/500", "ERROR"},
+		{"/healthz", "DEBUG"},
+		{"/missing", "WARN"},
+	}
+	for _, tt := range tests {
+		logs.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		got := records(t, logs)
+		if len(got) != 1 {
+			t.Fatalf("GET %s logged %d records, want 1", tt.target, len(got))
+		}
+		record := got[0]
+		if record["level"] != tt.level || record["msg"] != "request completed" || record["path"] != tt.target {
+			t.Errorf("GET %s logged %v, want level %s", tt.target, record, tt.level)
+		}
+		if record["request_id"] == "" || record["request_id"] == nil {
+			t.Errorf("GET %s logged no request ID", tt.target)
+		}
+		if strings.HasPrefix(tt.target, "/songs/") && (record["route"] != "/songs/:id" || record["song_id"] != strings.TrimPrefix(tt.target, "/songs/")) {
+			t.Errorf("GET %s logged route %v and song %v", tt.target, record["route"], record["song_id"])
+		}
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	logs := captureLogs(t)
+	r := newRouter()
+	// A stand-in for apperr.Middleware, which renders the recorded error.
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			c.String(http.StatusInternalServerError, c.Errors.Last().Error())
+		}
+	}, Recovery())
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(Header, "req-42")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError || w.Body.String() != "panic: boom" {
+		t.Errorf("got %d %q, want 500 with the recorded panic", w.Code, w.Body)
+	}
+
+	got := records(t, logs)
+	if len(got) != 2 {
+		t.Fatalf("logged %d records, want the panic and the request", len(got))
+	}
+	panicked, completed := got[0], got[1]
+	if panicked["msg"] != "panic while serving request" || panicked["request_id"] != "req-42" || panicked["error"] != "boom" {
+		t.Errorf("panic record = %v", panicked)
+	}
+	if stack, _ := panicked["stack"].(string); !strings.Contains(stack, "TestRecovery") {
+		t.Errorf("panic record has no stack: %q", stack)
+	}
+	if completed["level"] != "ERROR" || completed["status"] != 500.0 || completed["errors"] == nil {
+		t.Errorf("access record = %v, want an error with status 500", completed)
+	}
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"context"
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/internal/storage/repository"
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// failingSongs fails List from its failAt-th call on.
+type failingSongs struct {
+	repository.SongRepository
+	calls, failAt int
+}
+
+func (s *failingSongs) List(ctx context.Context, filter repository.SongFilter) ([]models.Song, error) {
+	s.calls++
+	if s.calls >= s.failAt {
+		return nil, errors.New("connection reset")
+	}
+	return s.SongRepository.List(ctx, filter)
+}
+
+func TestExportFailureRemovesXLSXTempFiles(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	group, err := store.Groups().FirstOrCreate(ctx, "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A chunk of long texts, escaped in the sheet, is more than the stream
+	// writer keeps in memory, so it moves the rows to a temporary file.
+	text := strings.Repeat("&", excelize.TotalCellChars)
+	for i := range 500 {
+		song := &models.Song{GroupId: group.ID, Title: fmt.Sprintf("Song %d", i), Text: text}
+		if err := store.Songs().Create(ctx, song); err != nil {
+			t.Fatal(err)
+		}
+	}
+	songs := &failingSongs{SongRepository: store.Songs(), failAt: 2}
+	r := Router(Handlers{Export: controllers.NewExportController(songio.NewExporter(songs))})
+
+	w := serve(r, http.MethodGet, "/export?format=xlsx", "")
+	if songs.calls != 2 {
+		t.Fatalf("export read %d chunks, want it to fail on the second", songs.calls)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", w.Code)
+	}
+
+	left, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range left {
+		t.Errorf("temporary file %s left behind", entry.Name())
+	}
+}
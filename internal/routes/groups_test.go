@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+
+	var group models.Group
+	decode(t, serve(r, http.MethodPost, "/groups", `{"name": "Muse"}`), http.StatusCreated, &group)
+	if w := serve(r, http.MethodPost, "/groups", `{"name": "Muse"}`); w.Code != http.StatusConflict {
+		t.Errorf("POST /groups with taken name: got status %d, want 409", w.Code)
+	}
+	if w := serve(r, http.MethodPost, "/groups", `{"name": ""}`); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /groups without name: got status %d, want 400", w.Code)
+	}
+
+	serve(r, http.MethodPost, "/groups", `{"name": "ABBA"}`)
+	if w := serve(r, http.MethodPatch, "/groups/1", `{"name": "ABBA"}`); w.Code != http.StatusConflict {
+		t.Errorf("PATCH /groups/1 to taken name: got status %d, want 409", w.Code)
+	}
+	if w := serve(r, http.MethodPatch, "/groups/1", `{"name": "MUSE"}`); w.Code != http.StatusOK {
+		t.Errorf("PATCH /groups/1: got status %d, want 200", w.Code)
+	}
+	decode(t, serve(r, http.MethodGet, "/groups/1", ""), http.StatusOK, &group)
+	if group.Name != "MUSE" {
+		t.Errorf("group 1 is called %q after rename, want MUSE", group.Name)
+	}
+}
+
+func TestGroupNamesAreTrimmed(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse ", "song": "Uprising"}`)
+	serve(r, http.MethodPost, "/info", `{"group": " Muse", "song": "Starlight"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Queen", "song": "Bohemian Rhapsody"}`)
+
+	var songs []models.Song
+	decode(t, serve(r, http.MethodGet, "/songs?group=Muse&match=exact", ""), http.StatusOK, &songs)
+	if len(songs) != 2 || songs[0].GroupId != songs[1].GroupId || songs[0].GroupName != "Muse" {
+		t.Errorf("songs of Muse = %+v, want both songs in one group", songs)
+	}
+
+	if w := serve(r, http.MethodPost, "/groups", `{"name": "  Muse  "}`); w.Code != http.StatusConflict {
+		t.Errorf("POST /groups with a padded taken name: got status %d, want 409", w.Code)
+	}
+	// Reassigned to the existing group rather than to a new " Queen ".
+	serve(r, http.MethodPatch, "/songs/1?group_mode=reassign", `{"group_name": " Queen "}`)
+	var song models.Song
+	decode(t, serve(r, http.MethodGet, "/songs/1", ""), http.StatusOK, &song)
+	if song.GroupName != "Queen" || song.GroupId != 2 {
+		t.Errorf("song 1 is in group %d %q, want 2 Queen", song.GroupId, song.GroupName)
+	}
+	// Renamed to the trimmed name.
+	serve(r, http.MethodPatch, "/songs/2", `{"group_name": "Muse\t"}`)
+	decode(t, serve(r, http.MethodGet, "/songs/2", ""), http.StatusOK, &song)
+	if song.GroupName != "Muse" {
+		t.Errorf("song 2 is in group %q, want Muse", song.GroupName)
+	}
+
+	var page struct {
+		Total int64 `json:"total"`
+	}
+	decode(t, serve(r, http.MethodGet, "/groups", ""), http.StatusOK, &page)
+	if page.Total != 2 {
+		t.Errorf("GET /groups total = %d, want 2", page.Total)
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	for _, name := range []string{"Muse", "ABBA", "Queen", "Museum"} {
+		serve(r, http.MethodPost, "/groups", `{"name": "`+name+`"}`)
+	}
+
+	var page struct {
+		Items []models.Group `json:"items"`
+		Total int64          `json:"total"`
+		Page  int            `json:"page"`
+		Limit int            `json:"limit"`
+	}
+	decode(t, serve(r, http.MethodGet, "/groups?name=mus&page=2&limit=1", ""), http.StatusOK, &page)
+	if len(page.Items) != 1 || page.Items[0].Name != "Museum" {
+		t.Errorf("GET /groups page 2 = %+v, want Museum", page.Items)
+	}
+	if page.Total != 2 || page.Page != 2 || page.Limit != 1 {
+		t.Errorf("GET /groups total, page, limit = %d, %d, %d, want 2, 2, 1", page.Total, page.Page, page.Limit)
+	}
+}
+
+func TestDeleteGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		// wantGroup is the group of the song afterwards, zero when the song
+		// is deleted with it.
+		wantGroup uint
+	}{
+		{"restrict", "", http.StatusConflict, 1},
+		{"cascade", "?songs=cascade", http.StatusOK, 0},
+		{"reassign", "?songs=reassign&target_id=2", http.StatusOK, 2},
+		{"reassign without target", "?songs=reassign", http.StatusBadRequest, 1},
+		{"reassign to itself", "?songs=reassign&target_id=1", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRouter(controllers.SongControllerConfig{})
+			serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+			serve(r, http.MethodPost, "/groups", `{"name": "ABBA"}`)
+
+			if w := serve(r, http.MethodDelete, "/groups/1"+tt.query, ""); w.Code != tt.status {
+				t.Fatalf("DELETE: got status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+
+			w := serve(r, http.MethodGet, "/songs?song=Uprising", "")
+			if tt.wantGroup == 0 {
+				if w.Code != http.StatusNotFound {
+					t.Errorf("song survived its group: got status %d", w.Code)
+				}
+				if w := serve(r, http.MethodGet, "/songs/1", ""); w.Code != http.StatusNotFound {
+					t.Errorf("GET /songs/1 after cascade: got status %d, want 404", w.Code)
+				}
+				return
+			}
+			var songs []models.Song
+			decode(t, w, http.StatusOK, &songs)
+			if len(songs) != 1 || songs[0].GroupId != tt.wantGroup {
+				t.Errorf("songs %+v, want one in group %d", songs, tt.wantGroup)
+			}
+
+			var song models.Song
+			decode(t, serve(r, http.MethodGet, "/songs/1", ""), http.StatusOK, &song)
+			if song.GroupId != tt.wantGroup {
+				t.Errorf("GET /songs/1: song is in group %d, want %d", song.GroupId, tt.wantGroup)
+			}
+		})
+	}
+}
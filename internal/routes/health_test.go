@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"context"
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/health"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newHealthRouter serves the API with the given readiness checker.
+func newHealthRouter(checker *health.Checker) http.Handler {
+	return Router(Handlers{Health: controllers.NewHealthController(checker)})
+}
+
+func TestReadyz(t *testing.T) {
+	var dbErr error
+	database := health.Check{Name: "database", Critical: true, Run: func(context.Context) (string, error) { return "", dbErr }}
+	upstream := health.Check{Name: "enrichment_api", Run: func(context.Context) (string, error) { return "", errors.New("circuit open") }}
+	checker := health.NewChecker(time.Second, database, upstream)
+	r := newHealthRouter(checker)
+
+	// A degraded upstream does not make the service unready.
+	var report health.Report
+	decode(t, serve(r, http.MethodGet, "/readyz", ""), http.StatusOK, &report)
+	if !report.Ready || report.Components["enrichment_api"].Status != health.StatusDegraded {
+		t.Errorf("report = %+v, want ready with a degraded upstream", report)
+	}
+
+	dbErr = errors.New("connection refused")
+	decode(t, serve(r, http.MethodGet, "/readyz", ""), http.StatusServiceUnavailable, &report)
+	if db := report.Components["database"]; report.Ready || db.Status != health.StatusDown || db.Message != "connection refused" {
+		t.Errorf("report = %+v, want the database down", report)
+	}
+
+	dbErr = nil
+	checker.SetDraining()
+	decode(t, serve(r, http.MethodGet, "/readyz", ""), http.StatusServiceUnavailable, &report)
+	if report.Ready || !report.Draining {
+		t.Errorf("report after SetDraining = %+v, want draining", report)
+	}
+
+	// Liveness does not depend on readiness.
+	if w := serve(r, http.MethodGet, "/healthz", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /healthz while draining: got status %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,225 @@
+package routes
+
+import (
+	"bytes"
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/controllers"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const uprisingLRC = "[al:The Resistance]\n[00:01.00]Paranoia is in bloom\n[00:04.50]The PR transmissions will resume\n"
+
+// multipartLRC returns a form with the LRC file in its file field, and the
+// content type of the form.
+func multipartLRC(t *testing.T, lrc string) (string, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "uprising.lrc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte(lrc))
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return body.String(), form.FormDataContentType()
+}
+
+type lyricsResponse struct {
+	Synced bool              `json:"synced"`
+	Tags   map[string]string `json:"tags"`
+	Lines  []struct {
+		Time   string `json:"time"`
+		TimeMs *int64 `json:"time_ms"`
+		Text   string `json:"text"`
+	} `json:"lines"`
+}
+
+func TestSongLyricsFormats(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPatch, "/songs/1", `{"text": "Paranoia is in bloom"}`)
+
+	// Without synced lyrics there is no LRC, and the lines have no timings.
+	if w := serve(r, http.MethodGet, "/songs/1/lyrics?format=lrc", ""); w.Code != http.StatusNotFound {
+		t.Errorf("LRC before upload: got status %d, want 404", w.Code)
+	}
+	var plain lyricsResponse
+	decode(t, serve(r, http.MethodGet, "/songs/1/lyrics", ""), http.StatusOK, &plain)
+	if plain.Synced || len(plain.Lines) != 1 || plain.Lines[0].TimeMs != nil {
+		t.Errorf("JSON before upload = %+v", plain)
+	}
+
+	body, contentType := multipartLRC(t, uprisingLRC)
+	if w := serve(r, http.MethodPut, "/songs/1/lyrics", body, "Content-Type", contentType); w.Code != http.StatusOK {
+		t.Fatalf("multipart upload: got status %d: %s", w.Code, w.Body)
+	}
+
+	w := serve(r, http.MethodGet, "/songs/1/lyrics?format=lrc", "")
+	want := "[ti:Uprising]\n[ar:Muse]\n" + uprisingLRC
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("LRC = %d %q, want %q", w.Code, w.Body, want)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="song-1.lrc"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+
+	var synced lyricsResponse
+	decode(t, serve(r, http.MethodGet, "/songs/1/lyrics?format=json", ""), http.StatusOK, &synced)
+	if !synced.Synced || synced.Tags["al"] != "The Resistance" || len(synced.Lines) != 2 {
+		t.Fatalf("JSON = %+v", synced)
+	}
+	if line := synced.Lines[1]; line.Time != "00:04.50" || line.TimeMs == nil || *line.TimeMs != 4500 {
+		t.Errorf("second line = %+v, want 00:04.50", line)
+	}
+
+	w = serve(r, http.MethodGet, "/songs/1/lyrics?format=txt", "")
+	if w.Code != http.StatusOK || w.Body.String() != "Paranoia is in bloom\nThe PR transmissions will resume" {
+		t.Errorf("text = %d %q", w.Code, w.Body)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("text Content-Type = %q", w.Header().Get("Content-Type"))
+	}
+
+	if w := serve(r, http.MethodGet, "/songs/1/lyrics?format=srt", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: got status %d, want 400", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/songs/2/lyrics", ""); w.Code != http.StatusNotFound {
+		t.Errorf("missing song: got status %d, want 404", w.Code)
+	}
+}
+
+func TestUploadLyricsRejectsInvalidFiles(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+
+	var problem struct {
+		apperr.Problem
+		Line int `json:"line"`
+	}
+	lrc := "[00:04.50]The PR transmissions will resume\n[00:01.00]Paranoia is in bloom\n"
+	decode(t, serve(r, http.MethodPut, "/songs/1/lyrics", lrc, "Content-Type", "text/plain"), http.StatusBadRequest, &problem)
+	if problem.Code != apperr.CodeValidation || problem.Line != 2 {
+		t.Errorf("timestamps out of order = %+v, want a validation error on line 2", problem)
+	}
+
+	large := "[00:01.00]" + strings.Repeat("la", 1<<19) + "\n"
+	decode(t, serve(r, http.MethodPut, "/songs/1/lyrics", large, "Content-Type", "text/plain"), http.StatusRequestEntityTooLarge, &problem)
+	if problem.Code != apperr.CodeTooLarge {
+		t.Errorf("large file = %+v", problem)
+	}
+
+	body, contentType := multipartLRC(t, large)
+	if w := serve(r, http.MethodPut, "/songs/1/lyrics", body, "Content-Type", contentType); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large multipart file: got status %d, want 413: %s", w.Code, w.Body)
+	}
+
+	// Nothing was stored.
+	if w := serve(r, http.MethodGet, "/songs/1/lyrics?format=lrc", ""); w.Code != http.StatusNotFound {
+		t.Errorf("LRC after rejected uploads: got status %d, want 404", w.Code)
+	}
+}
+
+func TestSongSectionsAndLines(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPatch, "/songs/1", `{"text": "[Verse 1]\nline 1\nline 2\n\n[Chorus]\nline 3\n\n[Verse 2]\nline 4\nline 5"}`)
+
+	var section struct {
+		ID        string   `json:"id"`
+		Type      string   `json:"type"`
+		Ordinal   int      `json:"ordinal"`
+		FirstLine int      `json:"first_line"`
+		LastLine  int      `json:"last_line"`
+		Lines     []string `json:"lines"`
+	}
+	decode(t, serve(r, http.MethodGet, "/songs/1/sections/verse-2", ""), http.StatusOK, &section)
+	if section.ID != "verse-2" || section.FirstLine != 4 || section.LastLine != 5 || len(section.Lines) != 2 || section.Lines[1] != "line 5" {
+		t.Errorf("verse-2 = %+v", section)
+	}
+	// Without an ordinal the first section of the type is returned.
+	decode(t, serve(r, http.MethodGet, "/songs/1/sections/chorus", ""), http.StatusOK, &section)
+	if section.ID != "chorus-1" || section.FirstLine != 3 || section.LastLine != 3 {
+		t.Errorf("chorus = %+v", section)
+	}
+
+	sectionErrors := []struct {
+		target string
+		status int
+	}{
+		{"/songs/1/sections/verse-3", http.StatusNotFound},
+		{"/songs/1/sections/bridge", http.StatusNotFound},
+		{"/songs/1/sections/solo", http.StatusBadRequest},
+		{"/songs/1/sections/verse-0", http.StatusBadRequest},
+		{"/songs/2/sections/verse-1", http.StatusNotFound},
+	}
+	for _, tt := range sectionErrors {
+		if w := serve(r, http.MethodGet, tt.target, ""); w.Code != tt.status {
+			t.Errorf("GET %s: got status %d, want %d", tt.target, w.Code, tt.status)
+		}
+	}
+
+	type lines struct {
+		From  int `json:"from"`
+		To    int `json:"to"`
+		Total int `json:"total"`
+		Lines []struct {
+			Number  int    `json:"number"`
+			Text    string `json:"text"`
+			Section string `json:"section"`
+		} `json:"lines"`
+	}
+	ranges := []struct {
+		query    string
+		from, to int
+	}{
+		{"", 1, 5},
+		{"?from=2&to=4", 2, 4},
+		{"?from=3&to=3", 3, 3},
+		// A range running past the end of the text is cut short.
+		{"?from=4&to=99", 4, 5},
+	}
+	for _, tt := range ranges {
+		var got lines
+		decode(t, serve(r, http.MethodGet, "/songs/1/lines"+tt.query, ""), http.StatusOK, &got)
+		if got.From != tt.from || got.To != tt.to || got.Total != 5 || len(got.Lines) != tt.to-tt.from+1 {
+			t.Errorf("lines%s = %+v, want %d to %d", tt.query, got, tt.from, tt.to)
+			continue
+		}
+		if first := got.Lines[0]; first.Number != tt.from || first.Text != fmt.Sprintf("line %d", tt.from) {
+			t.Errorf("lines%s start with %+v", tt.query, first)
+		}
+	}
+	var got lines
+	decode(t, serve(r, http.MethodGet, "/songs/1/lines?from=3&to=4", ""), http.StatusOK, &got)
+	if got.Lines[0].Section != "chorus-1" || got.Lines[1].Section != "verse-2" {
+		t.Errorf("sections of lines 3 and 4 = %s, %s", got.Lines[0].Section, got.Lines[1].Section)
+	}
+
+	lineErrors := []struct {
+		query  string
+		status int
+		code   apperr.Code
+	}{
+		{"?from=4&to=2", http.StatusBadRequest, apperr.CodeValidation},
+		{"?from=0", http.StatusBadRequest, apperr.CodeValidation},
+		{"?to=many", http.StatusBadRequest, apperr.CodeValidation},
+		{"?from=6", http.StatusNotFound, apperr.CodeNotFound},
+		{"?from=6&to=9", http.StatusNotFound, apperr.CodeNotFound},
+	}
+	for _, tt := range lineErrors {
+		var problem apperr.Problem
+		decode(t, serve(r, http.MethodGet, "/songs/1/lines"+tt.query, ""), tt.status, &problem)
+		if problem.Code != tt.code {
+			t.Errorf("lines%s: code %s, want %s", tt.query, problem.Code, tt.code)
+		}
+	}
+	if w := serve(r, http.MethodGet, "/songs/2/lines", ""); w.Code != http.StatusNotFound {
+		t.Errorf("lines of a missing song: got status %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,147 @@
+package routes
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSongRevisions(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`, "X-Actor", "alice")
+	serve(r, http.MethodPatch, "/songs/1", `{"text": "one\ntwo"}`, "X-Actor", "bob")
+	serve(r, http.MethodPatch, "/groups/1", `{"name": "MUSE"}`)
+
+	var revisions []models.Revision
+	decode(t, serve(r, http.MethodGet, "/songs/1/revisions", ""), http.StatusOK, &revisions)
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2: %+v", len(revisions), revisions)
+	}
+	created, updated := revisions[1], revisions[0]
+	if created.Action != models.RevisionActionCreate || created.Actor != "alice" {
+		t.Errorf("first revision = %s by %s, want create by alice", created.Action, created.Actor)
+	}
+	if updated.Changes["text"] != (models.FieldChange{After: "one\ntwo"}) || updated.Actor != "bob" {
+		t.Errorf("second revision = %+v by %s", updated.Changes, updated.Actor)
+	}
+
+	var diff struct {
+		Changes  map[string]models.FieldChange `json:"changes"`
+		TextDiff []struct {
+			Op   string `json:"op"`
+			Text string `json:"text"`
+		} `json:"text_diff"`
+	}
+	decode(t, serve(r, http.MethodGet, "/songs/1/revisions/diff?from="+itoa(created.ID), ""), http.StatusOK, &diff)
+	if len(diff.TextDiff) != 2 || diff.TextDiff[0].Op != "+" {
+		t.Errorf("diff from the first revision = %+v", diff)
+	}
+
+	// The revert keeps the song in its renamed group rather than creating
+	// a new one with the old name.
+	var song models.Song
+	decode(t, serve(r, http.MethodPost, "/songs/1/revisions/"+itoa(created.ID)+"/revert", ""), http.StatusOK, &song)
+	if song.Text != "" || song.GroupId != 1 || song.GroupName != "MUSE" {
+		t.Errorf("reverted song = %+v", song)
+	}
+	decode(t, serve(r, http.MethodGet, "/songs/1/revisions?limit=1", ""), http.StatusOK, &revisions)
+	if revisions[0].Action != models.RevisionActionRevert || revisions[0].RevertOf == nil || *revisions[0].RevertOf != created.ID {
+		t.Errorf("latest revision = %+v, want a revert of %d", revisions[0], created.ID)
+	}
+
+	if w := serve(r, http.MethodGet, "/songs/2/revisions/"+itoa(created.ID), ""); w.Code != http.StatusNotFound {
+		t.Errorf("revision of another song: got status %d, want 404", w.Code)
+	}
+}
+
+// songActions returns the actions of the revisions of a song, oldest first.
+func songActions(t *testing.T, r http.Handler, id uint) []string {
+	t.Helper()
+	var revisions []models.Revision
+	decode(t, serve(r, http.MethodGet, "/songs/"+itoa(id)+"/revisions", ""), http.StatusOK, &revisions)
+	actions := make([]string, len(revisions))
+	for i, revision := range revisions {
+		actions[len(revisions)-1-i] = revision.Action + " by " + revision.Actor
+	}
+	return actions
+}
+
+func TestSongDeleteRevisions(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodDelete, "/songs/1", "", "X-Actor", "alice")
+	serve(r, http.MethodPost, "/songs/1/restore", "", "X-Actor", "bob")
+	serve(r, http.MethodDelete, "/songs/1", "", "X-Actor", "\x7fbad")
+	if w := serve(r, http.MethodDelete, "/songs/trash?older_than=0s", ""); w.Code != http.StatusOK {
+		t.Fatalf("DELETE /songs/trash: got status %d: %s", w.Code, w.Body)
+	}
+
+	want := "create by anonymous, delete by alice, restore by bob, delete by anonymous, purge by anonymous"
+	if got := strings.Join(songActions(t, r, 1), ", "); got != want {
+		t.Errorf("revisions = %s, want %s", got, want)
+	}
+}
+
+func TestGroupDeleteRevisions(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Queen", "song": "Bohemian Rhapsody"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Blur", "song": "Song 2"}`)
+
+	if w := serve(r, http.MethodDelete, "/groups/1?songs=cascade", "", "X-Actor", "alice"); w.Code != http.StatusOK {
+		t.Fatalf("cascade delete: got status %d: %s", w.Code, w.Body)
+	}
+	if got, want := strings.Join(songActions(t, r, 1), ", "), "create by anonymous, delete by alice"; got != want {
+		t.Errorf("revisions of the cascaded song = %s, want %s", got, want)
+	}
+
+	if w := serve(r, http.MethodDelete, "/groups/2?songs=reassign&target_id=3", "", "X-Actor", "bob"); w.Code != http.StatusOK {
+		t.Fatalf("reassign delete: got status %d: %s", w.Code, w.Body)
+	}
+	var revisions []models.Revision
+	decode(t, serve(r, http.MethodGet, "/songs/2/revisions?limit=1", ""), http.StatusOK, &revisions)
+	reassigned := revisions[0]
+	if reassigned.Action != models.RevisionActionUpdate || reassigned.Actor != "bob" {
+		t.Errorf("revision of the reassigned song = %s by %s, want update by bob", reassigned.Action, reassigned.Actor)
+	}
+	if change := reassigned.Changes["group_name"]; change != (models.FieldChange{Before: "Queen", After: "Blur"}) {
+		t.Errorf("group_name change = %+v, want Queen to Blur", change)
+	}
+}
+
+func TestDiffSongRevisionsRejectsBadRevisions(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Starlight"}`)
+	var own, other []models.Revision
+	decode(t, serve(r, http.MethodGet, "/songs/1/revisions", ""), http.StatusOK, &own)
+	decode(t, serve(r, http.MethodGet, "/songs/2/revisions", ""), http.StatusOK, &other)
+	from, foreign := itoa(own[0].ID), itoa(other[0].ID)
+
+	tests := []struct {
+		query  string
+		status int
+		field  string
+	}{
+		{"", http.StatusBadRequest, "from"},
+		{"from=abc", http.StatusBadRequest, "from"},
+		{"from=0", http.StatusBadRequest, "from"},
+		{"from=" + from + "&to=-2", http.StatusBadRequest, "to"},
+		{"from=99", http.StatusNotFound, ""},
+		{"from=" + from + "&to=99", http.StatusNotFound, ""},
+		{"from=" + foreign, http.StatusNotFound, ""},
+		{"from=" + from + "&to=" + foreign, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var problem apperr.Problem
+			decode(t, serve(r, http.MethodGet, "/songs/1/revisions/diff?"+tt.query, ""), tt.status, &problem)
+			if tt.field != "" && (len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field) {
+				t.Errorf("errors = %+v, want one for %s", problem.Errors, tt.field)
+			}
+		})
+	}
+}
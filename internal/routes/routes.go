@@ -2,41 +2,116 @@ package routes
 
 import (
 	_ "effectiveMobileTask/docs"
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/audit"
 	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/metrics"
+	"effectiveMobileTask/internal/requestlog"
+	"effectiveMobileTask/internal/tracing"
 	"effectiveMobileTask/lib/logger"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+type Handlers struct {
+	Songs  *controllers.SongController
+	Jobs   *controllers.JobController
+	Groups *controllers.GroupController
+	Import *controllers.ImportController
+	Export *controllers.ExportController
+	Health *controllers.HealthController
+}
+
 // Router godoc
 // @title Title Management API
 // @version 1.0
 // @description API for managing song information
 // @host localhost:8080
 // @BasePath /
-func Router() *gin.Engine {
-	r := gin.Default()
+func Router(h Handlers) *gin.Engine {
+	r := gin.New()
+	// metrics.Middleware wraps the problem rendering and the recovery, so
+	// that it sees the status of failed and panicking requests.
+	r.Use(tracing.Middleware(), requestlog.Middleware(), audit.Middleware(), metrics.Middleware(), apperr.Middleware(), requestlog.Recovery())
+	r.NoRoute(apperr.NoRoute)
+	// Probe endpoints
+	// @Tags Health
+	// @Summary Liveness, readiness and build information
+	r.GET("/healthz", h.Health.Healthz)
+	r.GET("/readyz", h.Health.Readyz)
+	r.GET("/version", h.Health.Version)
+	r.GET("/metrics", metrics.Handler())
 	// Info endpoint
 	// @Tags Songs
 	// @Summary Add song information
-	r.POST("/info", controllers.AddSongInfo)
+	r.POST("/info", h.Songs.AddSongInfo)
 	// Songs list endpoint
 	// @Tags Songs
 	// @Summary List songs
-	r.GET("/songs", controllers.GetSongs)
+	r.GET("/songs", h.Songs.GetSongs)
+	// Full-text search endpoint
+	// @Tags Songs
+	// @Summary Search songs
+	r.GET("/search", h.Songs.SearchSongs)
+	// Trash endpoints
+	// @Tags Songs
+	// @Summary List, restore and purge deleted songs
+	r.GET("/songs/trash", h.Songs.ListTrash)
+	r.DELETE("/songs/trash", h.Songs.PurgeTrash)
+	r.POST("/songs/:id/restore", h.Songs.RestoreSong)
+	// Single song endpoint
+	// @Tags Songs
+	// @Summary Get a song
+	r.GET("/songs/:id", h.Songs.GetSong)
 	// Title text endpoint
 	// @Tags Songs
 	// @Summary Get song text
-	r.GET("/songs/:id/text", controllers.GetSongText)
+	r.GET("/songs/:id/text", h.Songs.GetSongText)
+	// Structured lyrics endpoints
+	// @Tags Lyrics
+	// @Summary Get song sections and lines
+	r.GET("/songs/:id/sections", h.Songs.GetSongSections)
+	r.GET("/songs/:id/sections/:section", h.Songs.GetSongSection)
+	r.GET("/songs/:id/lines", h.Songs.GetSongLines)
+	r.GET("/songs/:id/lyrics", h.Songs.GetSongLyrics)
+	r.PUT("/songs/:id/lyrics", h.Songs.UploadSongLyrics)
+	// Revision history endpoints
+	// @Tags Revisions
+	// @Summary List, compare and revert song revisions
+	r.GET("/songs/:id/revisions", h.Songs.ListSongRevisions)
+	r.GET("/songs/:id/revisions/diff", h.Songs.DiffSongRevisions)
+	r.GET("/songs/:id/revisions/:revision", h.Songs.GetSongRevision)
+	r.POST("/songs/:id/revisions/:revision/revert", h.Songs.RevertSong)
 	// Update song endpoint
 	// @Tags Songs
 	// @Summary Update a song
-	r.PATCH("/songs/:id", controllers.UpdateSong)
+	r.PATCH("/songs/:id", h.Songs.UpdateSong)
 	// Delete song endpoint
 	// @Tags Songs
 	// @Summary Delete a song
-	r.DELETE("/songs/:id", controllers.DeleteSong)
+	r.DELETE("/songs/:id", h.Songs.DeleteSong)
+	// Enrichment job endpoint
+	// @Tags Jobs
+	// @Summary Get enrichment job status
+	r.GET("/jobs/:id", h.Jobs.GetJob)
+	// Groups endpoints
+	// @Tags Groups
+	// @Summary Manage groups
+	r.GET("/groups", h.Groups.ListGroups)
+	r.POST("/groups", h.Groups.CreateGroup)
+	r.GET("/groups/:id", h.Groups.GetGroup)
+	r.PATCH("/groups/:id", h.Groups.RenameGroup)
+	r.DELETE("/groups/:id", h.Groups.DeleteGroup)
+	r.POST("/groups/:id/merge", h.Groups.MergeGroups)
+	// Bulk import endpoint
+	// @Tags Import
+	// @Summary Import songs from a file
+	r.POST("/import", h.Import.ImportSongs)
+	// Catalog export endpoint
+	// @Tags Import
+	// @Summary Export songs to a file
+	r.GET("/export", h.Export.ExportSongs)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	logger.Info("docs documentation is available at http://localhost:8080/swagger/index.html")
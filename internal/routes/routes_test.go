@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"effectiveMobileTask/internal/apperr"
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/health"
+	"effectiveMobileTask/internal/songio"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	_ = logger.Setup("error", logger.FormatJSON, io.Discard)
+}
+
+// newTestRouter serves the API from an in-memory store.
+func newTestRouter(cfg controllers.SongControllerConfig) *gin.Engine {
+	store := repository.NewMemoryStore()
+	enricher := enrichment.NewNoopEnricher()
+	return Router(Handlers{
+		Songs:  controllers.NewSongController(store.Songs(), store.Groups(), store.Revisions(), enricher, cfg),
+		Jobs:   controllers.NewJobController(store.Jobs()),
+		Groups: controllers.NewGroupController(store.Groups()),
+		Import: controllers.NewImportController(songio.NewImporter(store.Songs(), store.Groups(), enricher)),
+		Export: controllers.NewExportController(songio.NewExporter(store.Songs())),
+		Health: controllers.NewHealthController(health.NewChecker(time.Second)),
+	})
+}
+
+// serve sends a request to the router and returns the recorded response.
+func serve(r http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func itoa(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// decode unmarshals the body of a response that must have the given status.
+func decode(t *testing.T, w *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("got status %d, want %d: %s", w.Code, status, w.Body)
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response body %s: %v", w.Body, err)
+	}
+}
+
+func TestMetricsRecordErrorStatus(t *testing.T) {
+	// The metrics are global, so the routes are only served by this test.
+	r := newTestRouter(controllers.SongControllerConfig{})
+	r.GET("/test/missing", func(c *gin.Context) { apperr.Abort(c, apperr.NotFound("missing")) })
+	r.GET("/test/panic", func(*gin.Context) { panic("boom") })
+
+	if w := serve(r, http.MethodGet, "/test/missing", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("GET /test/missing: got status %d, want 404", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/test/panic", ""); w.Code != http.StatusInternalServerError {
+		t.Fatalf("GET /test/panic: got status %d, want 500", w.Code)
+	}
+
+	body := serve(r, http.MethodGet, "/metrics", "").Body.String()
+	for _, series := range []string{
+		`music_http_request_duration_seconds_count{method="GET",route="/test/missing",status="404"} 1`,
+		`music_http_request_duration_seconds_count{method="GET",route="/test/panic",status="500"} 1`,
+	} {
+		if !strings.Contains(body, series) {
+			t.Errorf("metrics lack %s", series)
+		}
+	}
+}
+
+func TestListEndpointsRejectInvalidPagination(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+
+	targets := []string{"/songs", "/groups", "/search?q=muse", "/songs/trash", "/songs/1/revisions", "/songs/1/text"}
+	params := []struct {
+		query, field string
+	}{
+		{"page=0", "page"},
+		{"page=abc", "page"},
+		{"limit=-1", "limit"},
+		{"limit=101", "limit"},
+	}
+	for _, target := range targets {
+		for _, param := range params {
+			sep := "?"
+			if strings.Contains(target, "?") {
+				sep = "&"
+			}
+			url := target + sep + param.query
+			t.Run(url, func(t *testing.T) {
+				var problem apperr.Problem
+				decode(t, serve(r, http.MethodGet, url, ""), http.StatusBadRequest, &problem)
+				if len(problem.Errors) != 1 || problem.Errors[0].Field != param.field {
+					t.Errorf("got errors %+v, want one for %s", problem.Errors, param.field)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package routes
+
+import (
+	"effectiveMobileTask/internal/controllers"
+	"effectiveMobileTask/internal/models"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGroupRenameChangesSongETag(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+
+	etag := serve(r, http.MethodGet, "/songs/1", "").Header().Get("ETag")
+	if w := serve(r, http.MethodPatch, "/groups/1", `{"name": "MUSE"}`); w.Code != http.StatusOK {
+		t.Fatalf("PATCH /groups/1: got status %d: %s", w.Code, w.Body)
+	}
+
+	w := serve(r, http.MethodGet, "/songs/1", "", "If-None-Match", etag)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /songs/1 after rename: got status %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("ETag"); got == etag {
+		t.Errorf("ETag stayed %s after the group was renamed", got)
+	}
+}
+
+func TestRestoreChangesSongETag(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+
+	etag := serve(r, http.MethodGet, "/songs/1", "").Header().Get("ETag")
+	if w := serve(r, http.MethodDelete, "/songs/1", "", "If-Match", etag); w.Code != http.StatusOK {
+		t.Fatalf("DELETE /songs/1: got status %d: %s", w.Code, w.Body)
+	}
+	w := serve(r, http.MethodPost, "/songs/1/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /songs/1/restore: got status %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("ETag"); got == etag || got == "" {
+		t.Errorf("restored song has ETag %q, want a new one", got)
+	}
+
+	if w := serve(r, http.MethodPatch, "/songs/1", `{"link": "https://example.com"}`, "If-Match", etag); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with the ETag from before the delete: got status %d, want 412", w.Code)
+	}
+}
+
+func TestUploadLyricsChecksIfMatch(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{RequireIfMatch: true})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	etag := serve(r, http.MethodGet, "/songs/1", "").Header().Get("ETag")
+	const lrc = "[00:01.00]Paranoia is in bloom\n"
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"missing", "", http.StatusPreconditionRequired},
+		{"stale", `"99"`, http.StatusPreconditionFailed},
+		{"current", etag, http.StatusOK},
+		{"replaced", etag, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		var headers []string
+		if tt.ifMatch != "" {
+			headers = []string{"If-Match", tt.ifMatch, "Content-Type", "text/plain"}
+		}
+		w := serve(r, http.MethodPut, "/songs/1/lyrics", lrc, headers...)
+		if w.Code != tt.want {
+			t.Errorf("%s If-Match: got status %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+		}
+		if w.Code == http.StatusOK && w.Header().Get("ETag") == etag {
+			t.Errorf("%s If-Match: ETag stayed %s", tt.name, etag)
+		}
+	}
+}
+
+func TestSongLifecycle(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+
+	if w := serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`); w.Code != http.StatusOK {
+		t.Fatalf("POST /info: got status %d: %s", w.Code, w.Body)
+	}
+	if w := serve(r, http.MethodPost, "/info", `{"group": "Muse"`); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /info with a broken body: got status %d, want 400", w.Code)
+	}
+	if w := serve(r, http.MethodPost, "/info", `{"group": "Muse"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /info without song: got status %d, want 400", w.Code)
+	}
+
+	var song models.Song
+	decode(t, serve(r, http.MethodGet, "/songs/1", ""), http.StatusOK, &song)
+	if song.GroupName != "Muse" || song.Title != "Uprising" || song.Version != 1 {
+		t.Errorf("GET /songs/1 = %+v", song)
+	}
+
+	w := serve(r, http.MethodPatch, "/songs/1", `{"text": "one\ntwo\n\nthree"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH /songs/1: got status %d: %s", w.Code, w.Body)
+	}
+
+	var text struct {
+		Text []string `json:"text"`
+	}
+	decode(t, serve(r, http.MethodGet, "/songs/1/text?page=2&limit=1", ""), http.StatusOK, &text)
+	if len(text.Text) != 1 || text.Text[0] != "three" {
+		t.Errorf("GET /songs/1/text page 2 = %q, want [three]", text.Text)
+	}
+
+	var songs []models.Song
+	decode(t, serve(r, http.MethodGet, "/songs?group=mus", ""), http.StatusOK, &songs)
+	if len(songs) != 1 || songs[0].ID != 1 {
+		t.Errorf("GET /songs?group=mus = %+v, want song 1", songs)
+	}
+
+	if w := serve(r, http.MethodDelete, "/songs/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("DELETE /songs/1: got status %d: %s", w.Code, w.Body)
+	}
+	if w := serve(r, http.MethodGet, "/songs/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /songs/1 after delete: got status %d, want 404", w.Code)
+	}
+	decode(t, serve(r, http.MethodGet, "/songs/trash", ""), http.StatusOK, &songs)
+	if len(songs) != 1 || songs[0].ID != 1 {
+		t.Errorf("GET /songs/trash = %+v, want song 1", songs)
+	}
+
+	if w := serve(r, http.MethodPost, "/songs/1/restore", ""); w.Code != http.StatusOK {
+		t.Fatalf("POST /songs/1/restore: got status %d: %s", w.Code, w.Body)
+	}
+	if w := serve(r, http.MethodGet, "/songs/1", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /songs/1 after restore: got status %d, want 200", w.Code)
+	}
+}
+
+func TestFilterByReleaseDate(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Starlight"}`)
+	// Stored with an ISO date, filtered with both formats below.
+	serve(r, http.MethodPatch, "/songs/1", `{"release_date": "2009-07-16"}`)
+	serve(r, http.MethodPatch, "/songs/2", `{"release_date": "03.09.2006"}`)
+
+	tests := []struct {
+		query string
+		want  []uint
+	}{
+		{"release_date=2009-07-16", []uint{1}},
+		{"release_date=16.07.2009", []uint{1}},
+		{"release_from=2007-01-01", []uint{1}},
+		{"release_to=2007-01-01", []uint{2}},
+		{"release_from=01.01.2006&release_to=2009-07-16", []uint{1, 2}},
+	}
+	for _, tt := range tests {
+		var songs []models.Song
+		decode(t, serve(r, http.MethodGet, "/songs?"+tt.query, ""), http.StatusOK, &songs)
+		got := make([]uint, len(songs))
+		for i, song := range songs {
+			got[i] = song.ID
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("GET /songs?%s = songs %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"release_date=16/07/2009", "release_from=2009-7-16", "release_to=yesterday"} {
+		if w := serve(r, http.MethodGet, "/songs?"+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /songs?%s: got status %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestUpdateSongGroupMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		groupName string
+		status    int
+		// wantGroup is the group of song 2 afterwards and wantOther that
+		// of song 3, which shares its group.
+		wantGroup uint
+		wantName  string
+		wantOther string
+	}{
+		{"rename", "", "ABBA!", http.StatusOK, 2, "ABBA!", "ABBA!"},
+		{"rename to taken name", "", "Muse", http.StatusConflict, 2, "ABBA", "ABBA"},
+		{"reassign to existing group", "?group_mode=reassign", "Muse", http.StatusOK, 1, "Muse", "ABBA"},
+		{"reassign to new group", "?group_mode=reassign", "Queen", http.StatusOK, 3, "Queen", "ABBA"},
+		{"unknown mode", "?group_mode=merge", "Muse", http.StatusBadRequest, 2, "ABBA", "ABBA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRouter(controllers.SongControllerConfig{})
+			serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+			serve(r, http.MethodPost, "/info", `{"group": "ABBA", "song": "SOS"}`)
+			serve(r, http.MethodPost, "/info", `{"group": "ABBA", "song": "Waterloo"}`)
+
+			body := `{"group_name": "` + tt.groupName + `", "song": "S.O.S."}`
+			if w := serve(r, http.MethodPatch, "/songs/2"+tt.query, body); w.Code != tt.status {
+				t.Fatalf("PATCH: got status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+
+			var song, other models.Song
+			decode(t, serve(r, http.MethodGet, "/songs/2", ""), http.StatusOK, &song)
+			decode(t, serve(r, http.MethodGet, "/songs/3", ""), http.StatusOK, &other)
+			if song.GroupId != tt.wantGroup || song.GroupName != tt.wantName {
+				t.Errorf("song 2 is in group %d %q, want %d %q", song.GroupId, song.GroupName, tt.wantGroup, tt.wantName)
+			}
+			if other.GroupName != tt.wantOther {
+				t.Errorf("song 3 is in group %q, want %q", other.GroupName, tt.wantOther)
+			}
+			// A failed update changes nothing, the title included.
+			if wantTitle := map[bool]string{true: "S.O.S.", false: "SOS"}[tt.status == http.StatusOK]; song.Title != wantTitle {
+				t.Errorf("song 2 is called %q, want %q", song.Title, wantTitle)
+			}
+		})
+	}
+}
+
+func TestSongPreconditions(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{RequireIfMatch: true})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	etag := serve(r, http.MethodGet, "/songs/1", "").Header().Get("ETag")
+
+	if w := serve(r, http.MethodGet, "/songs/1", "", "If-None-Match", etag); w.Code != http.StatusNotModified {
+		t.Errorf("GET with current If-None-Match: got status %d, want 304", w.Code)
+	}
+	if w := serve(r, http.MethodPatch, "/songs/1", `{"text": "la"}`); w.Code != http.StatusPreconditionRequired {
+		t.Errorf("PATCH without If-Match: got status %d, want 428", w.Code)
+	}
+	if w := serve(r, http.MethodPatch, "/songs/1", `{"text": "la"}`, "If-Match", `W/`+etag); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with weak If-Match: got status %d, want 412", w.Code)
+	}
+
+	w := serve(r, http.MethodPatch, "/songs/1", `{"text": "la"}`, "If-Match", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("PATCH with current If-Match: got status %d and ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+	if w := serve(r, http.MethodDelete, "/songs/1", "", "If-Match", etag); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("DELETE with stale If-Match: got status %d, want 412", w.Code)
+	}
+	if w := serve(r, http.MethodDelete, "/songs/1", "", "If-Match", "*"); w.Code != http.StatusOK {
+		t.Errorf("DELETE with If-Match *: got status %d, want 200", w.Code)
+	}
+}
+
+func TestAddSongAsyncQueuesJob(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{AsyncEnrichment: true})
+
+	var accepted struct {
+		SongID           uint   `json:"song_id"`
+		JobID            uint   `json:"job_id"`
+		EnrichmentStatus string `json:"enrichment_status"`
+	}
+	w := serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	decode(t, w, http.StatusAccepted, &accepted)
+	if accepted.EnrichmentStatus != models.EnrichmentStatusPending {
+		t.Errorf("enrichment_status = %q, want %q", accepted.EnrichmentStatus, models.EnrichmentStatusPending)
+	}
+	if got, want := w.Header().Get("Location"), "/jobs/"+itoa(accepted.JobID); got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+
+	var job models.EnrichmentJob
+	decode(t, serve(r, http.MethodGet, "/jobs/"+itoa(accepted.JobID), ""), http.StatusOK, &job)
+	if job.SongID != accepted.SongID || job.Status != models.JobStatusQueued || job.Group != "Muse" || job.Song != "Uprising" {
+		t.Errorf("job = %+v, want a queued job for song %d", job, accepted.SongID)
+	}
+}
+
+func TestSongIDMustBePositive(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, "/songs/%s", ""},
+		{http.MethodGet, "/songs/%s/text", ""},
+		{http.MethodGet, "/songs/%s/sections", ""},
+		{http.MethodGet, "/songs/%s/lyrics", ""},
+		{http.MethodGet, "/songs/%s/revisions", ""},
+		{http.MethodGet, "/songs/%s/revisions/1", ""},
+		{http.MethodPatch, "/songs/%s", `{"link": "https://example.com"}`},
+		{http.MethodDelete, "/songs/%s", ""},
+		{http.MethodPost, "/songs/%s/restore", ""},
+	}
+	for _, id := range []string{"0", "-1", "abc"} {
+		for _, req := range requests {
+			target := fmt.Sprintf(req.path, id)
+			if w := serve(r, req.method, target, req.body); w.Code != http.StatusBadRequest {
+				t.Errorf("%s %s: got status %d, want 400", req.method, target, w.Code)
+			}
+		}
+	}
+}
+
+func TestExportIgnoresPagination(t *testing.T) {
+	r := newTestRouter(controllers.SongControllerConfig{})
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Uprising"}`)
+	serve(r, http.MethodPost, "/info", `{"group": "Muse", "song": "Resistance"}`)
+
+	w := serve(r, http.MethodGet, "/export?format=ndjson&page=0&limit=1000", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /export: got status %d: %s", w.Code, w.Body)
+	}
+	if lines := strings.Count(w.Body.String(), "\n"); lines != 2 {
+		t.Errorf("GET /export returned %d songs, want 2:\n%s", lines, w.Body)
+	}
+}
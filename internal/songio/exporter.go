@@ -0,0 +1,49 @@
+package songio
+
+import (
+	"context"
+	"effectiveMobileTask/internal/storage/repository"
+)
+
+// exportChunkSize is how many songs are read from the database at a time.
+const exportChunkSize = 500
+
+type Exporter struct {
+	songs repository.SongRepository
+}
+
+func NewExporter(songs repository.SongRepository) *Exporter {
+	return &Exporter{songs: songs}
+}
+
+// Export writes every song matching the filter, in the order of its sort
+// keys. Songs are read in chunks with a cursor, so memory use does not grow
+// with the catalog. The offset, limit and cursor of the filter are ignored.
+// It returns the number of songs written; closing the writer is left to the
+// caller.
+func (ex *Exporter) Export(ctx context.Context, filter repository.SongFilter, w RecordWriter) (int, error) {
+	if len(filter.Sort) == 0 {
+		filter.Sort = repository.DefaultSongSort
+	}
+	filter.Offset, filter.Limit, filter.Cursor = 0, exportChunkSize, nil
+
+	written := 0
+	for {
+		songs, err := ex.songs.List(ctx, filter)
+		if err != nil {
+			return written, err
+		}
+		for _, song := range songs {
+			if err := w.Write(NewRecord(song)); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if len(songs) < filter.Limit {
+			return written, nil
+		}
+
+		cursor := repository.NewSongCursor(songs[len(songs)-1], filter.Sort, false)
+		filter.Cursor = &cursor
+	}
+}
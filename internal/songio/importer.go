@@ -0,0 +1,308 @@
+package songio
+
+import (
+	"context"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/internal/validation"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kinds of ImportIssue.
+const (
+	IssueInvalid     = "invalid"
+	IssueDuplicate   = "duplicate"
+	IssueFailed      = "failed"
+	IssueNotEnriched = "not_enriched"
+)
+
+// ErrUnreadable is returned by Import when the file cannot be read to the
+// end.
+var ErrUnreadable = errors.New("import file cannot be read")
+
+// maxIssues keeps the report of a large, broken file reasonably small.
+const maxIssues = 1000
+
+type ImportOptions struct {
+	// DryRun validates and deduplicates the records without writing
+	// anything. Enrichment is skipped.
+	DryRun bool
+	// Enrich looks up the release date, text and link of records missing
+	// any of them. Only the missing fields are filled in.
+	Enrich bool
+}
+
+// ImportIssue describes a record that was not imported, or was imported
+// without enrichment.
+type ImportIssue struct {
+	Row     int    `json:"row" example:"3"`
+	Kind    string `json:"kind" example:"invalid"`
+	Field   string `json:"field,omitempty" example:"release_date"`
+	Message string `json:"message" example:"expected DD.MM.YYYY or YYYY-MM-DD"`
+	// SongID is the existing song a duplicate matches.
+	SongID uint `json:"song_id,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	Rows   int  `json:"rows" example:"120"`
+	// Created counts the songs added, or that would be added in a dry run.
+	Created         int           `json:"created" example:"115"`
+	Duplicates      int           `json:"duplicates" example:"3"`
+	Invalid         int           `json:"invalid" example:"2"`
+	Failed          int           `json:"failed" example:"0"`
+	Enriched        int           `json:"enriched" example:"40"`
+	Issues          []ImportIssue `json:"issues"`
+	IssuesTruncated bool          `json:"issues_truncated,omitempty"`
+	// Error tells why the file could not be read to the end. Records before
+	// the failure have been imported.
+	Error string `json:"error,omitempty"`
+}
+
+func (r *ImportReport) addIssue(issue ImportIssue) {
+	if len(r.Issues) >= maxIssues {
+		r.IssuesTruncated = true
+		return
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+type Importer struct {
+	songs    repository.SongRepository
+	groups   repository.GroupRepository
+	enricher enrichment.SongEnricher
+}
+
+func NewImporter(songs repository.SongRepository, groups repository.GroupRepository, enricher enrichment.SongEnricher) *Importer {
+	return &Importer{
+		songs:    songs,
+		groups:   groups,
+		enricher: enricher,
+	}
+}
+
+// Import adds the songs read from the reader one by one. Invalid records and
+// songs that already exist, in the database or earlier in the file, are
+// skipped and listed in the report. An error is returned, along with the
+// report so far, when the import has to stop early: ErrUnreadable when the
+// file cannot be read to the end, or the error of the database.
+func (im *Importer) Import(ctx context.Context, reader RecordReader, opts ImportOptions) (*ImportReport, error) {
+	run := importRun{
+		Importer:   im,
+		opts:       opts,
+		report:     &ImportReport{DryRun: opts.DryRun, Issues: make([]ImportIssue, 0)},
+		groupCache: make(map[string]*models.Group),
+		seen:       make(map[string]int),
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			run.report.Error = err.Error()
+			return run.report, err
+		}
+
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var recordErr *RecordError
+			if errors.As(err, &recordErr) {
+				run.report.Rows++
+				run.report.Invalid++
+				run.report.addIssue(ImportIssue{Row: recordErr.Row, Kind: IssueInvalid, Message: recordErr.Err.Error()})
+				continue
+			}
+			run.report.Error = err.Error()
+			return run.report, fmt.Errorf("%w: %w", ErrUnreadable, err)
+		}
+
+		run.report.Rows++
+		if err := run.importRecord(ctx, record); err != nil {
+			run.report.Error = err.Error()
+			return run.report, err
+		}
+	}
+
+	logger.FromContext(ctx).Info("songs imported",
+		slog.Bool("dry_run", opts.DryRun),
+		slog.Int("rows", run.report.Rows),
+		slog.Int("created", run.report.Created),
+		slog.Int("duplicates", run.report.Duplicates),
+		slog.Int("invalid", run.report.Invalid),
+		slog.Int("failed", run.report.Failed))
+	return run.report, nil
+}
+
+type importRun struct {
+	*Importer
+	opts   ImportOptions
+	report *ImportReport
+	// groupCache holds groups by name; nil means the group does not exist
+	// yet, which only happens in a dry run.
+	groupCache map[string]*models.Group
+	// seen maps group and title of the records so far to their row.
+	seen map[string]int
+}
+
+// importRecord returns an error only when the import has to stop; problems
+// with the record itself end up in the report.
+func (run *importRun) importRecord(ctx context.Context, record Record) error {
+	song, issue := parseRecord(record)
+	if issue != nil {
+		run.report.Invalid++
+		run.report.addIssue(*issue)
+		return nil
+	}
+	groupName := strings.TrimSpace(record.Group)
+
+	key := groupName + "\x00" + song.Title
+	if row, ok := run.seen[key]; ok {
+		run.report.Duplicates++
+		run.report.addIssue(ImportIssue{Row: record.Row, Kind: IssueDuplicate, Message: fmt.Sprintf("same group and song as row %d", row)})
+		return nil
+	}
+	run.seen[key] = record.Row
+
+	group, err := run.group(ctx, groupName)
+	if err != nil {
+		return err
+	}
+
+	if group != nil {
+		existing, err := run.songs.GetByGroupAndTitle(ctx, group.ID, song.Title)
+		if err == nil {
+			run.report.Duplicates++
+			run.report.addIssue(ImportIssue{Row: record.Row, Kind: IssueDuplicate, Message: "song already exists", SongID: existing.ID})
+			return nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+	}
+
+	if run.opts.DryRun {
+		run.report.Created++
+		return nil
+	}
+
+	song.GroupId = group.ID
+	song.EnrichmentStatus = models.EnrichmentStatusEnriched
+	if run.opts.Enrich && (song.ReleaseDate.IsZero() || song.Text == "" || song.Link == "") {
+		if err := run.enrich(ctx, groupName, song); err != nil {
+			song.EnrichmentStatus = models.EnrichmentStatusFailed
+			run.report.addIssue(ImportIssue{Row: record.Row, Kind: IssueNotEnriched, Message: err.Error()})
+		} else {
+			run.report.Enriched++
+		}
+	}
+
+	if err := run.songs.Create(ctx, song); err != nil {
+		logger.FromContext(ctx).Error("failed to import song", slog.Int("row", record.Row), slog.Any("error", err))
+		run.report.Failed++
+		run.report.addIssue(ImportIssue{Row: record.Row, Kind: IssueFailed, Message: "failed to save song"})
+		return nil
+	}
+	run.report.Created++
+	return nil
+}
+
+// group looks the group up, creating it unless this is a dry run.
+func (run *importRun) group(ctx context.Context, name string) (*models.Group, error) {
+	if group, ok := run.groupCache[name]; ok {
+		return group, nil
+	}
+
+	var group *models.Group
+	var err error
+	if run.opts.DryRun {
+		group, err = run.groups.GetByName(ctx, name)
+		if errors.Is(err, repository.ErrNotFound) {
+			group, err = nil, nil
+		}
+	} else {
+		group, err = run.groups.FirstOrCreate(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	run.groupCache[name] = group
+	return group, nil
+}
+
+// enrich fills in the fields the record left empty. It fails when the
+// enricher has none of them either.
+func (run *importRun) enrich(ctx context.Context, group string, song *models.Song) error {
+	detail, err := run.enricher.Enrich(ctx, group, song.Title)
+	if err != nil {
+		return err
+	}
+
+	filled := false
+	if song.Text == "" && detail.Text != "" {
+		song.Text = detail.Text
+		filled = true
+	}
+	if song.Link == "" && detail.Link != "" {
+		song.Link = detail.Link
+		filled = true
+	}
+	if song.ReleaseDate.IsZero() && detail.ReleaseDate != "" {
+		date, err := models.ParseReleaseDate(detail.ReleaseDate)
+		if err != nil {
+			return fmt.Errorf("invalid release date from %s: %w", run.enricher.Name(), err)
+		}
+		song.ReleaseDate = date
+		filled = true
+	}
+	if !filled {
+		return enrichment.ErrNotFound
+	}
+	return nil
+}
+
+// parseRecord validates the record and turns it into a song.
+func parseRecord(record Record) (*models.Song, *ImportIssue) {
+	invalid := func(field, message string) (*models.Song, *ImportIssue) {
+		return nil, &ImportIssue{Row: record.Row, Kind: IssueInvalid, Field: field, Message: message}
+	}
+
+	group := strings.TrimSpace(record.Group)
+	title := strings.TrimSpace(record.Song)
+	switch {
+	case group == "":
+		return invalid("group", "is required")
+	case utf8.RuneCountInString(group) > models.MaxNameLength:
+		return invalid("group", fmt.Sprintf("is longer than %d characters", models.MaxNameLength))
+	case title == "":
+		return invalid("song", "is required")
+	case utf8.RuneCountInString(title) > models.MaxNameLength:
+		return invalid("song", fmt.Sprintf("is longer than %d characters", models.MaxNameLength))
+	}
+
+	song := &models.Song{Title: title, Text: record.Text, Link: strings.TrimSpace(record.Link)}
+
+	if value := strings.TrimSpace(record.ReleaseDate); value != "" {
+		date, err := models.ParseReleaseDate(value)
+		if err != nil {
+			return invalid("release_date", "expected DD.MM.YYYY or YYYY-MM-DD")
+		}
+		song.ReleaseDate = date
+	}
+
+	if song.Link != "" {
+		if !validation.IsHTTPURL(song.Link) {
+			return invalid("link", "expected an http or https URL")
+		}
+	}
+
+	return song, nil
+}
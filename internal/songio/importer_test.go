@@ -0,0 +1,222 @@
+package songio
+
+import (
+	"context"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"strings"
+	"testing"
+)
+
+// stubEnricher returns a fixed detail or error and counts its calls.
+type stubEnricher struct {
+	detail models.SongDetail
+	err    error
+	calls  int
+}
+
+func (e *stubEnricher) Name() string {
+	return "stub"
+}
+
+func (e *stubEnricher) Enrich(context.Context, string, string) (models.SongDetail, error) {
+	e.calls++
+	return e.detail, e.err
+}
+
+// importNDJSON imports the lines into the store.
+func importNDJSON(t *testing.T, store *repository.MemoryStore, enricher enrichment.SongEnricher, opts ImportOptions, lines ...string) *ImportReport {
+	t.Helper()
+	reader, err := NewRecordReader(FormatNDJSON, strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := NewImporter(store.Songs(), store.Groups(), enricher).Import(context.Background(), reader, opts)
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	return report
+}
+
+// seedSong adds a song to the store and returns it.
+func seedSong(t *testing.T, store *repository.MemoryStore, group, title string) *models.Song {
+	t.Helper()
+	ctx := context.Background()
+	g, err := store.Groups().FirstOrCreate(ctx, group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	song := &models.Song{GroupId: g.ID, Title: title}
+	if err := store.Songs().Create(ctx, song); err != nil {
+		t.Fatal(err)
+	}
+	return song
+}
+
+func TestImportSkipsDuplicates(t *testing.T) {
+	store := repository.NewMemoryStore()
+	existing := seedSong(t, store, "Muse", "Uprising")
+
+	report := importNDJSON(t, store, &stubEnricher{}, ImportOptions{},
+		`{"group": "Muse", "song": "Uprising"}`,
+		`{"group": "Queen", "song": "Bohemian Rhapsody"}`,
+		`{"group": " Queen ", "song": "Bohemian Rhapsody"}`,
+		`{"group": "Queen"}`,
+	)
+
+	if report.Rows != 4 || report.Created != 1 || report.Duplicates != 2 || report.Invalid != 1 {
+		t.Errorf("report = %+v, want 4 rows, 1 created, 2 duplicates, 1 invalid", report)
+	}
+	want := []ImportIssue{
+		{Row: 1, Kind: IssueDuplicate, Message: "song already exists", SongID: existing.ID},
+		{Row: 3, Kind: IssueDuplicate, Message: "same group and song as row 2"},
+		{Row: 4, Kind: IssueInvalid, Field: "song", Message: "is required"},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("issues = %+v, want %+v", report.Issues, want)
+	}
+	for i := range want {
+		if report.Issues[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, report.Issues[i], want[i])
+		}
+	}
+
+	if count, _ := store.Songs().Count(context.Background(), repository.SongFilter{}); count != 2 {
+		t.Errorf("store holds %d songs, want 2", count)
+	}
+}
+
+func TestImportDryRunWritesNothing(t *testing.T) {
+	store := repository.NewMemoryStore()
+	seedSong(t, store, "Muse", "Uprising")
+	enricher := &stubEnricher{detail: models.SongDetail{Text: "text"}}
+
+	report := importNDJSON(t, store, enricher, ImportOptions{DryRun: true, Enrich: true},
+		`{"group": "Muse", "song": "Uprising"}`,
+		`{"group": "Muse", "song": "Starlight"}`,
+		`{"group": "Queen", "song": "Bohemian Rhapsody"}`,
+		`{"group": "Queen", "song": "Bohemian Rhapsody"}`,
+	)
+
+	if !report.DryRun || report.Created != 2 || report.Duplicates != 2 || report.Enriched != 0 {
+		t.Errorf("report = %+v, want 2 created and 2 duplicates in a dry run", report)
+	}
+	ctx := context.Background()
+	if count, _ := store.Songs().Count(ctx, repository.SongFilter{}); count != 1 {
+		t.Errorf("store holds %d songs after a dry run, want 1", count)
+	}
+	if count, _ := store.Groups().Count(ctx, repository.GroupFilter{}); count != 1 {
+		t.Errorf("store holds %d groups after a dry run, want 1", count)
+	}
+	if enricher.calls != 0 {
+		t.Errorf("enricher called %d times in a dry run", enricher.calls)
+	}
+}
+
+func TestImportEnrich(t *testing.T) {
+	detail := models.SongDetail{ReleaseDate: "16.07.2009", Text: "upstream text", Link: "https://example.com/upstream"}
+
+	tests := []struct {
+		name         string
+		enrich       bool
+		enricher     *stubEnricher
+		record       string
+		wantCalls    int
+		wantEnriched int
+		wantStatus   string
+		wantText     string
+		wantLink     string
+		wantDate     string
+		wantIssue    string
+	}{
+		{
+			name:       "off",
+			enricher:   &stubEnricher{detail: detail},
+			record:     `{"group": "Muse", "song": "Uprising"}`,
+			wantStatus: models.EnrichmentStatusEnriched,
+		},
+		{
+			name:         "fills missing fields only",
+			enrich:       true,
+			enricher:     &stubEnricher{detail: detail},
+			record:       `{"group": "Muse", "song": "Uprising", "text": "own text"}`,
+			wantCalls:    1,
+			wantEnriched: 1,
+			wantStatus:   models.EnrichmentStatusEnriched,
+			wantText:     "own text",
+			wantLink:     detail.Link,
+			wantDate:     "16.07.2009",
+		},
+		{
+			name:         "ISO release date",
+			enrich:       true,
+			enricher:     &stubEnricher{detail: models.SongDetail{ReleaseDate: "2009-07-16"}},
+			record:       `{"group": "Muse", "song": "Uprising"}`,
+			wantCalls:    1,
+			wantEnriched: 1,
+			wantStatus:   models.EnrichmentStatusEnriched,
+			wantDate:     "16.07.2009",
+		},
+		{
+			name:       "invalid release date",
+			enrich:     true,
+			enricher:   &stubEnricher{detail: models.SongDetail{ReleaseDate: "July 2009"}},
+			record:     `{"group": "Muse", "song": "Uprising"}`,
+			wantCalls:  1,
+			wantStatus: models.EnrichmentStatusFailed,
+			wantIssue:  IssueNotEnriched,
+		},
+		{
+			name:       "complete record is not looked up",
+			enrich:     true,
+			enricher:   &stubEnricher{detail: detail},
+			record:     `{"group": "Muse", "song": "Uprising", "release_date": "2009-07-16", "text": "own text", "link": "https://example.com/own"}`,
+			wantStatus: models.EnrichmentStatusEnriched,
+			wantText:   "own text",
+			wantLink:   "https://example.com/own",
+			wantDate:   "16.07.2009",
+		},
+		{
+			name:       "not found",
+			enrich:     true,
+			enricher:   &stubEnricher{err: enrichment.ErrNotFound},
+			record:     `{"group": "Muse", "song": "Uprising"}`,
+			wantCalls:  1,
+			wantStatus: models.EnrichmentStatusFailed,
+			wantIssue:  IssueNotEnriched,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			report := importNDJSON(t, store, tt.enricher, ImportOptions{Enrich: tt.enrich}, tt.record)
+
+			if report.Created != 1 || report.Enriched != tt.wantEnriched {
+				t.Errorf("report = %+v, want 1 created, %d enriched", report, tt.wantEnriched)
+			}
+			if tt.enricher.calls != tt.wantCalls {
+				t.Errorf("enricher called %d times, want %d", tt.enricher.calls, tt.wantCalls)
+			}
+			if tt.wantIssue != "" && (len(report.Issues) != 1 || report.Issues[0].Kind != tt.wantIssue) {
+				t.Errorf("issues = %+v, want one %s", report.Issues, tt.wantIssue)
+			}
+
+			song, err := store.Songs().GetByID(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if song.EnrichmentStatus != tt.wantStatus || song.Text != tt.wantText || song.Link != tt.wantLink {
+				t.Errorf("song = %s %q %q, want %s %q %q", song.EnrichmentStatus, song.Text, song.Link, tt.wantStatus, tt.wantText, tt.wantLink)
+			}
+			date := ""
+			if !song.ReleaseDate.IsZero() {
+				date = song.ReleaseDate.Format("02.01.2006")
+			}
+			if date != tt.wantDate {
+				t.Errorf("release date = %q, want %q", date, tt.wantDate)
+			}
+		})
+	}
+}
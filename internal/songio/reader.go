@@ -0,0 +1,256 @@
+// Package songio reads and writes song lists in CSV, JSON, NDJSON and XLSX.
+package songio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	// FormatXLSX can only be written.
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat accepts a format name, a content type or a file name.
+func ParseFormat(value string) (Format, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexByte(value, ';'); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	switch value {
+	case "csv", "text/csv":
+		return FormatCSV, true
+	case "json", "application/json":
+		return FormatJSON, true
+	case "ndjson", "jsonl", "application/x-ndjson", "application/jsonl":
+		return FormatNDJSON, true
+	case "xlsx", xlsxContentType:
+		return FormatXLSX, true
+	}
+
+	if ext := path.Ext(value); ext != "" && ext != value {
+		return ParseFormat(strings.TrimPrefix(ext, "."))
+	}
+	return "", false
+}
+
+// Record is a song as it appears in an import file. Values are not
+// validated.
+type Record struct {
+	// Row counts records from 1 in file order.
+	Row         int    `json:"-"`
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Link        string `json:"link,omitempty"`
+}
+
+// RecordError is a record that could not be decoded. Reading may go on with
+// the next record.
+type RecordError struct {
+	Row int
+	Err error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordReader returns records one at a time without loading the whole
+// file. Next returns io.EOF after the last record, a *RecordError for a
+// record that has to be skipped and any other error when the file cannot
+// be read further.
+type RecordReader interface {
+	Next() (Record, error)
+}
+
+func NewRecordReader(format Format, r io.Reader) (RecordReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSON:
+		return newJSONReader(r)
+	case FormatNDJSON:
+		return &ndjsonReader{scanner: newLineScanner(r)}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// csvColumns maps accepted header names to record fields.
+var csvColumns = map[string]string{
+	"group":        "group",
+	"group_name":   "group",
+	"song":         "song",
+	"title":        "song",
+	"release_date": "release_date",
+	"text":         "text",
+	"link":         "link",
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if field, ok := csvColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	for _, required := range []string{"group", "song"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header has no %s column", required)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (Record, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		r.row++
+		// The csv reader goes on with the next line after a parse error.
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, &RecordError{Row: r.row, Err: err}
+		}
+		return Record{}, err
+	}
+	r.row++
+
+	value := func(field string) string {
+		i, ok := r.columns[field]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+	return Record{
+		Row:         r.row,
+		Group:       value("group"),
+		Song:        value("song"),
+		ReleaseDate: value("release_date"),
+		Text:        value("text"),
+		Link:        value("link"),
+	}, nil
+}
+
+// jsonReader reads an array of objects element by element.
+type jsonReader struct {
+	decoder *json.Decoder
+	row     int
+	done    bool
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("json file must contain an array of songs")
+	}
+	return &jsonReader{decoder: decoder}, nil
+}
+
+func (r *jsonReader) Next() (Record, error) {
+	if r.done {
+		return Record{}, io.EOF
+	}
+	if !r.decoder.More() {
+		r.done = true
+		if _, err := r.decoder.Token(); err != nil {
+			return Record{}, fmt.Errorf("failed to read json: %w", err)
+		}
+		return Record{}, io.EOF
+	}
+
+	r.row++
+	var record Record
+	if err := r.decoder.Decode(&record); err != nil {
+		// A value of the wrong type is skipped by the decoder, anything
+		// else leaves it in the middle of the document.
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return Record{}, &RecordError{Row: r.row, Err: err}
+		}
+		return Record{}, fmt.Errorf("row %d: failed to read json: %w", r.row, err)
+	}
+	record.Row = r.row
+	return record, nil
+}
+
+// ndjsonReader reads one object per line; blank lines are skipped.
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (r *ndjsonReader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		r.row++
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return Record{}, &RecordError{Row: r.row, Err: err}
+		}
+		record.Row = r.row
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// maxLineSize bounds a single NDJSON line, which holds a whole song text.
+const maxLineSize = 1 << 20
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return scanner
+}
@@ -0,0 +1,162 @@
+package songio
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Format
+		wantOK bool
+	}{
+		{"csv", FormatCSV, true},
+		{" JSON ", FormatJSON, true},
+		{"application/json; charset=utf-8", FormatJSON, true},
+		{"application/x-ndjson", FormatNDJSON, true},
+		{"jsonl", FormatNDJSON, true},
+		{"songs.CSV", FormatCSV, true},
+		{"export.xlsx", FormatXLSX, true},
+		{"text/plain", "", false},
+		{"songs.txt", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseFormat(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseFormat(%q) = %q, %t, want %q, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// readAll reads records until io.EOF or an error that stops reading. It
+// returns the records and the rows of skipped records.
+func readAll(reader RecordReader) ([]Record, []int, error) {
+	records := make([]Record, 0)
+	skipped := make([]int, 0)
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records, skipped, nil
+		}
+		var recordErr *RecordError
+		if errors.As(err, &recordErr) {
+			skipped = append(skipped, recordErr.Row)
+			continue
+		}
+		if err != nil {
+			return records, skipped, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestRecordReader(t *testing.T) {
+	uprising := Record{Group: "Muse", Song: "Uprising", ReleaseDate: "07.09.2009"}
+	sos := Record{Group: "ABBA", Song: "SOS", Text: "Where are those happy days?\nThey seem so hard to find"}
+
+	tests := []struct {
+		name        string
+		format      Format
+		input       string
+		want        []Record
+		wantSkipped []int
+		wantErr     bool
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			input:  "\uFEFFGroup,Title,release_date,extra\nMuse,Uprising,07.09.2009,x\nABBA,SOS,,\n",
+			want:   []Record{withRow(uprising, 1), withRow(Record{Group: "ABBA", Song: "SOS"}, 2)},
+		},
+		{
+			name:   "csv with quoted text and short rows",
+			format: FormatCSV,
+			input:  "group_name,song,text\nABBA,SOS,\"Where are those happy days?\nThey seem so hard to find\"\nMuse\n",
+			want:   []Record{withRow(sos, 1), withRow(Record{Group: "Muse"}, 2)},
+		},
+		{
+			name:        "csv with a broken row",
+			format:      FormatCSV,
+			input:       "group,song\nMuse,\"Upri\"sing\nABBA,SOS\n",
+			want:        []Record{withRow(Record{Group: "ABBA", Song: "SOS"}, 2)},
+			wantSkipped: []int{1},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			input:  `[{"group": "Muse", "song": "Uprising", "release_date": "07.09.2009", "unknown": 1}, {"group": "ABBA", "song": "SOS", "text": "Where are those happy days?\nThey seem so hard to find"}]`,
+			want:   []Record{withRow(uprising, 1), withRow(sos, 2)},
+		},
+		{
+			name:        "json with a value of the wrong type",
+			format:      FormatJSON,
+			input:       `[{"group": "Muse", "song": 1}, {"group": "ABBA", "song": "SOS"}]`,
+			want:        []Record{withRow(Record{Group: "ABBA", Song: "SOS"}, 2)},
+			wantSkipped: []int{1},
+		},
+		{
+			name:    "json cut short",
+			format:  FormatJSON,
+			input:   `[{"group": "Muse", "song": "Uprising"}, {"group": `,
+			want:    []Record{withRow(Record{Group: "Muse", Song: "Uprising"}, 1)},
+			wantErr: true,
+		},
+		{
+			name:        "ndjson with blank and broken lines",
+			format:      FormatNDJSON,
+			input:       "{\"group\": \"Muse\", \"song\": \"Uprising\", \"release_date\": \"07.09.2009\"}\n\n{broken\n{\"group\": \"ABBA\", \"song\": \"SOS\"}\n",
+			want:        []Record{withRow(uprising, 1), withRow(Record{Group: "ABBA", Song: "SOS"}, 3)},
+			wantSkipped: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewRecordReader(tt.format, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("NewRecordReader: %v", err)
+			}
+			records, skipped, err := readAll(reader)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("records = %+v, want %+v", records, tt.want)
+			}
+			if tt.wantSkipped == nil {
+				tt.wantSkipped = []int{}
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped rows = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestNewRecordReaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{"empty csv", FormatCSV, ""},
+		{"csv without song column", FormatCSV, "group,text\nMuse,la\n"},
+		{"json object", FormatJSON, `{"group": "Muse"}`},
+		{"empty json", FormatJSON, ""},
+		{"xlsx", FormatXLSX, ""},
+	}
+	for _, tt := range tests {
+		if _, err := NewRecordReader(tt.format, strings.NewReader(tt.input)); err == nil {
+			t.Errorf("%s: NewRecordReader succeeded", tt.name)
+		}
+	}
+}
+
+func withRow(record Record, row int) Record {
+	record.Row = row
+	return record
+}
@@ -0,0 +1,249 @@
+package songio
+
+import (
+	"bufio"
+	"bytes"
+	"effectiveMobileTask/internal/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"unicode/utf8"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// csvHeader lists the columns of exported CSV files and XLSX sheets. Files
+// written with it can be imported back.
+var csvHeader = []string{"group", "song", "release_date", "text", "link"}
+
+// NewRecord turns a song into a record. The release date is formatted as
+// DD.MM.YYYY and left empty when unknown.
+func NewRecord(song models.Song) Record {
+	record := Record{Group: song.GroupName, Song: song.Title, Text: song.Text, Link: song.Link}
+	if !song.ReleaseDate.IsZero() {
+		record.ReleaseDate = song.ReleaseDate.Format("02.01.2006")
+	}
+	return record
+}
+
+func (r Record) fields() []string {
+	return []string{r.Group, r.Song, r.ReleaseDate, r.Text, r.Link}
+}
+
+// RecordWriter writes records one at a time. Close must be called to write
+// the end of the file, or Abort to give up on a file that is not finished;
+// neither closes the underlying writer.
+type RecordWriter interface {
+	Write(record Record) error
+	Close() error
+	// Abort releases the resources of the writer without writing the end
+	// of the file.
+	Abort() error
+}
+
+func NewRecordWriter(format Format, w io.Writer) (RecordWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSON:
+		return &jsonWriter{w: bufio.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// ContentType returns the media type of files in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return xlsxContentType
+	}
+	return "application/octet-stream"
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(record Record) error {
+	return w.writer.Write(record.fields())
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Abort() error {
+	return nil
+}
+
+// marshalRecord encodes the record without escaping HTML, which song texts
+// are not.
+func marshalRecord(record Record) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// jsonWriter writes an array of objects, one per line.
+type jsonWriter struct {
+	w       *bufio.Writer
+	written int
+}
+
+func (w *jsonWriter) Write(record Record) error {
+	data, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if w.written == 0 {
+		separator = "[\n"
+	}
+	w.written++
+	if _, err := w.w.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.written == 0 {
+		end = "[]\n"
+	}
+	if _, err := w.w.WriteString(end); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *jsonWriter) Abort() error {
+	return nil
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (w *ndjsonWriter) Write(record Record) error {
+	data, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.w.Flush()
+}
+
+func (w *ndjsonWriter) Abort() error {
+	return nil
+}
+
+// xlsxWriter writes a single sheet. Rows go through the excelize stream
+// writer, which keeps them in a temporary file rather than in memory; the
+// workbook is written out on Close.
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+const xlsxSheet = "Songs"
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	xw := &xlsxWriter{w: w, file: excelize.NewFile()}
+	if err := xw.start(); err != nil {
+		_ = xw.Abort()
+		return nil, err
+	}
+	return xw, nil
+}
+
+// start sets up the sheet and writes the header row.
+func (w *xlsxWriter) start() error {
+	if err := w.file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return err
+	}
+	var err error
+	if w.stream, err = w.file.NewStreamWriter(xlsxSheet); err != nil {
+		return err
+	}
+	for i, width := range []float64{24, 32, 14, 60, 40} {
+		if err := w.stream.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+	}
+	return w.writeRow(csvHeader)
+}
+
+func (w *xlsxWriter) Write(record Record) error {
+	return w.writeRow(record.fields())
+}
+
+func (w *xlsxWriter) writeRow(fields []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		values[i] = truncateCell(field)
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.w)
+	return err
+}
+
+// Abort removes the temporary files of the stream writer.
+func (w *xlsxWriter) Abort() error {
+	return w.file.Close()
+}
+
+// truncateCell cuts values that do not fit into a cell, which Excel limits
+// to 32767 characters.
+func truncateCell(value string) string {
+	if utf8.RuneCountInString(value) <= excelize.TotalCellChars {
+		return value
+	}
+	return string([]rune(value)[:excelize.TotalCellChars])
+}
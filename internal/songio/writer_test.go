@@ -0,0 +1,169 @@
+package songio
+
+import (
+	"bytes"
+	"context"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"encoding/json"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRecords = []Record{
+	{Group: "Muse", Song: "Uprising", ReleaseDate: "16.07.2009", Text: "Paranoia is in bloom,\nthe \"PR\" transmissions", Link: "https://example.com/uprising"},
+	{Group: "Queen", Song: "Bohemian Rhapsody"},
+}
+
+// write writes the records in the format and returns the file.
+func write(t *testing.T, format Format, records ...Record) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w, err := NewRecordWriter(format, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := string(write(t, FormatCSV, testRecords...))
+	want := "group,song,release_date,text,link\n" +
+		"Muse,Uprising,16.07.2009,\"Paranoia is in bloom,\nthe \"\"PR\"\" transmissions\",https://example.com/uprising\n" +
+		"Queen,Bohemian Rhapsody,,,\n"
+	if got != want {
+		t.Errorf("CSV = %q, want %q", got, want)
+	}
+
+	// The file can be imported back.
+	reader, err := NewRecordReader(FormatCSV, strings.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, skipped, err := readAll(reader)
+	if err != nil || len(skipped) != 0 {
+		t.Fatalf("reading the CSV back: %v, skipped %v", err, skipped)
+	}
+	for i := range records {
+		records[i].Row = 0
+	}
+	if !reflect.DeepEqual(records, testRecords) {
+		t.Errorf("records read back = %+v, want %+v", records, testRecords)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	if got := string(write(t, FormatJSON)); got != "[]\n" {
+		t.Errorf("empty JSON = %q, want []", got)
+	}
+
+	got := write(t, FormatJSON, testRecords...)
+	var records []Record
+	if err := json.Unmarshal(got, &records); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if !reflect.DeepEqual(records, testRecords) {
+		t.Errorf("records = %+v, want %+v", records, testRecords)
+	}
+	if lines := strings.Count(string(got), "\n"); lines != 4 {
+		t.Errorf("JSON has %d lines, want one per record and two for the brackets: %s", lines, got)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	got := string(write(t, FormatNDJSON, testRecords...))
+	want := `{"group":"Muse","song":"Uprising","release_date":"16.07.2009","text":"Paranoia is in bloom,\nthe \"PR\" transmissions","link":"https://example.com/uprising"}` + "\n" +
+		`{"group":"Queen","song":"Bohemian Rhapsody"}` + "\n"
+	if got != want {
+		t.Errorf("NDJSON = %q, want %q", got, want)
+	}
+}
+
+func TestExportJSONAcrossChunks(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	group, err := store.Groups().FirstOrCreate(ctx, "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const songs = 2*exportChunkSize + 1
+	for i := range songs {
+		song := &models.Song{GroupId: group.ID, Title: fmt.Sprintf("Song %04d", i), ReleaseDate: time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC)}
+		if err := store.Songs().Create(ctx, song); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b bytes.Buffer
+	w, err := NewRecordWriter(FormatJSON, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := NewExporter(store.Songs()).Export(ctx, repository.SongFilter{}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(b.Bytes(), &records); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if written != songs || len(records) != songs {
+		t.Fatalf("exported %d songs, decoded %d, want %d", written, len(records), songs)
+	}
+	for i, record := range records {
+		if want := fmt.Sprintf("Song %04d", i); record.Song != want || record.Group != "Muse" || record.ReleaseDate != "16.07.2009" {
+			t.Fatalf("record %d = %+v, want %s by Muse", i, record, want)
+		}
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	long := Record{Group: "Muse", Song: "Long", Text: strings.Repeat("я", excelize.TotalCellChars+10)}
+	got := write(t, FormatXLSX, append(testRecords, long)...)
+
+	file, err := excelize.OpenReader(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("invalid XLSX: %v", err)
+	}
+	defer file.Close()
+
+	if sheets := file.GetSheetList(); !reflect.DeepEqual(sheets, []string{xlsxSheet}) {
+		t.Errorf("sheets = %v, want [%s]", sheets, xlsxSheet)
+	}
+	rows, err := file.GetRows(xlsxSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want a header and 3 records", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], csvHeader) {
+		t.Errorf("header = %v, want %v", rows[0], csvHeader)
+	}
+	if !reflect.DeepEqual(rows[1], testRecords[0].fields()) {
+		t.Errorf("first record = %q, want %q", rows[1], testRecords[0].fields())
+	}
+	// Trailing empty cells are not returned.
+	if !reflect.DeepEqual(rows[2], []string{"Queen", "Bohemian Rhapsody"}) {
+		t.Errorf("second record = %q", rows[2])
+	}
+	if text := []rune(rows[3][3]); len(text) != excelize.TotalCellChars {
+		t.Errorf("long text has %d characters, want it cut to %d", len(text), excelize.TotalCellChars)
+	}
+}
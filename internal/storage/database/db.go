@@ -2,6 +2,7 @@ package database
 
 import (
 	"effectiveMobileTask/config"
+	"effectiveMobileTask/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"log"
@@ -18,10 +19,13 @@ func DbConnect() *gorm.DB {
 	once.Do(func() {
 		dsn := config.AppConfig.DB.URL
 		var err error
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 		if err != nil {
 			log.Fatal("failed to connect to the DB: ", err)
 		}
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			log.Fatal("failed to register the tracing plugin: ", err)
+		}
 
 		sqlDB, err := db.DB()
 		if err != nil {
@@ -33,3 +37,15 @@ func DbConnect() *gorm.DB {
 	})
 	return db
 }
+
+// Close closes the connection pool opened by DbConnect, if any.
+func Close() error {
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
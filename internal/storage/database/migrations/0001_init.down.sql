@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS enrichment_jobs;
+DROP TABLE IF EXISTS songs;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,50 @@
+-- Baseline schema. IF NOT EXISTS lets databases created by the former
+-- AutoMigrate call adopt versioned migrations without changes.
+CREATE TABLE IF NOT EXISTS groups (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uni_groups_name UNIQUE (name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_groups_name ON groups (name);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS songs (
+    id           BIGSERIAL PRIMARY KEY,
+    group_id     BIGINT,
+    group_name   TEXT,
+    title        TEXT,
+    release_date TIMESTAMPTZ,
+    text         TEXT,
+    link         TEXT,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ,
+    deleted_at   TIMESTAMPTZ,
+    CONSTRAINT fk_groups_songs FOREIGN KEY (group_id) REFERENCES groups (id)
+);
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS enrichment_status TEXT NOT NULL DEFAULT 'enriched';
+
+CREATE INDEX IF NOT EXISTS idx_songs_group_id ON songs (group_id);
+CREATE INDEX IF NOT EXISTS idx_songs_group_name ON songs (group_name);
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS enrichment_jobs (
+    id         BIGSERIAL PRIMARY KEY,
+    song_id    BIGINT,
+    "group"    TEXT,
+    song       TEXT,
+    status     TEXT,
+    attempts   BIGINT,
+    last_error TEXT,
+    run_at     TIMESTAMPTZ,
+    locked_at  TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrichment_jobs_song_id ON enrichment_jobs (song_id);
+CREATE INDEX IF NOT EXISTS idx_enrichment_jobs_pickup ON enrichment_jobs (status, run_at);
@@ -0,0 +1,5 @@
+-- Fails if a deleted group shares its name with another group; purge the
+-- trash before rolling back.
+DROP INDEX IF EXISTS idx_groups_name;
+CREATE INDEX idx_groups_name ON groups (name);
+ALTER TABLE groups ADD CONSTRAINT uni_groups_name UNIQUE (name);
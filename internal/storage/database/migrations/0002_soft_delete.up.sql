@@ -0,0 +1,5 @@
+-- Soft-deleted groups keep their row, so the name only has to be unique
+-- among groups that are not deleted.
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS uni_groups_name;
+DROP INDEX IF EXISTS idx_groups_name;
+CREATE UNIQUE INDEX idx_groups_name ON groups (name) WHERE deleted_at IS NULL;
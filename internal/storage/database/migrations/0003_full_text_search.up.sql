@@ -0,0 +1,10 @@
+-- Every song carries the text search configuration used to stem its
+-- lyrics. The vector is generated from it so it never goes stale.
+ALTER TABLE songs ADD COLUMN language REGCONFIG NOT NULL DEFAULT 'simple';
+
+ALTER TABLE songs ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector(language, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(language, coalesce(text, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_songs_search_vector ON songs USING GIN (search_vector);
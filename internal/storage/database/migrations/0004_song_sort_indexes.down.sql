@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_songs_updated_at;
+DROP INDEX IF EXISTS idx_songs_created_at;
+DROP INDEX IF EXISTS idx_songs_release_date;
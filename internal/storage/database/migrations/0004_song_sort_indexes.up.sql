@@ -0,0 +1,5 @@
+-- Keyset pages of GET /songs sorted by date read these indexes in order
+-- instead of sorting the whole table.
+CREATE INDEX idx_songs_release_date ON songs (release_date, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_songs_created_at ON songs (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_songs_updated_at ON songs (updated_at, id) WHERE deleted_at IS NULL;
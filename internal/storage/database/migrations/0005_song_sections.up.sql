@@ -0,0 +1,14 @@
+-- Sections are derived from songs.text by the application. Songs written
+-- before this migration get theirs the first time they are read.
+CREATE TABLE song_sections (
+    id         BIGSERIAL PRIMARY KEY,
+    song_id    BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    type       TEXT NOT NULL,
+    ordinal    INTEGER NOT NULL,
+    header     TEXT NOT NULL DEFAULT '',
+    first_line INTEGER NOT NULL,
+    lines      JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX idx_song_sections_position ON song_sections (song_id, position);
@@ -0,0 +1,7 @@
+CREATE TABLE synced_lyrics (
+    song_id    BIGINT PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    tags       JSONB NOT NULL DEFAULT '{}',
+    lines      JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+-- The version of a song grows with every change and serves as its ETag, so
+-- that concurrent updates cannot overwrite each other unnoticed.
+ALTER TABLE songs ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
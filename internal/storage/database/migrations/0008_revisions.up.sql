@@ -0,0 +1,15 @@
+-- Every change of a song or group, with the state of the record after it.
+-- Revisions outlive purged records, so entity_id is not a foreign key.
+CREATE TABLE revisions (
+    id          BIGSERIAL PRIMARY KEY,
+    entity_type TEXT NOT NULL,
+    entity_id   BIGINT NOT NULL,
+    action      TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    revert_of   BIGINT REFERENCES revisions (id),
+    changes     JSONB NOT NULL DEFAULT '{}',
+    state       JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ
+);
+
+CREATE INDEX idx_revisions_entity ON revisions (entity_type, entity_id, id);
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+type groupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{db: db}
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *models.Group) error {
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createGroup(tx, group)
+	}))
+}
+
+func (r *groupRepository) FirstOrCreate(ctx context.Context, name string) (*models.Group, error) {
+	var group *models.Group
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		group, err = firstOrCreateGroup(tx, name)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return group, nil
+}
+
+// firstOrCreateGroup returns the group with the given name, creating it when
+// there is none. The insert runs in a savepoint: if a concurrent transaction
+// has just created the same group, the conflict is rolled back and that
+// group is read instead.
+func firstOrCreateGroup(tx *gorm.DB, name string) (*models.Group, error) {
+	name = normalizeGroupName(name)
+	var group models.Group
+	err := tx.Where("name = ?", name).First(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		group = models.Group{Name: name}
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return createGroup(tx, &group)
+		})
+		if errors.Is(translateError(err), ErrConflict) {
+			group = models.Group{}
+			err = tx.Where("name = ?", name).First(&group).Error
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func createGroup(tx *gorm.DB, group *models.Group) error {
+	group.Name = normalizeGroupName(group.Name)
+	if err := tx.Create(group).Error; err != nil {
+		return err
+	}
+	return recordRevision(tx, models.RevisionEntityGroup, group.ID, models.RevisionActionCreate, nil, group.RevisionState())
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id uint) (*models.Group, error) {
+	var group models.Group
+	if err := r.db.WithContext(ctx).First(&group, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) GetByName(ctx context.Context, name string) (*models.Group, error) {
+	var group models.Group
+	if err := r.db.WithContext(ctx).Where("name = ?", normalizeGroupName(name)).First(&group).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) GetWithSongs(ctx context.Context, id uint) (*models.Group, error) {
+	var group models.Group
+	err := r.db.WithContext(ctx).
+		Preload("Songs", func(db *gorm.DB) *gorm.DB { return db.Order("songs.id") }).
+		First(&group, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	for i := range group.Songs {
+		group.Songs[i].GroupName = group.Name
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) List(ctx context.Context, filter GroupFilter) ([]models.Group, error) {
+	var groups []models.Group
+
+	if err := r.filtered(ctx, filter).Order("id").Offset(filter.Offset).Limit(filter.Limit).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *groupRepository) Count(ctx context.Context, filter GroupFilter) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *groupRepository) filtered(ctx context.Context, filter GroupFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Group{})
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(filter.Name)+"%")
+	}
+	return query
+}
+
+// normalizeGroupName trims the white space around a group name.
+func normalizeGroupName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// escapeLike makes % and _ in value match literally in a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *groupRepository) Rename(ctx context.Context, id uint, name string) error {
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return renameGroup(tx, id, name)
+	}))
+}
+
+func renameGroup(tx *gorm.DB, id uint, name string) error {
+	name = normalizeGroupName(name)
+	var group models.Group
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&group, id).Error; err != nil {
+		return err
+	}
+	before := group.RevisionState()
+
+	if err := tx.Model(&group).Update("name", name).Error; err != nil {
+		return err
+	}
+	// The songs show the name of their group, so they change as well.
+	err := tx.Unscoped().Model(&models.Song{}).Where("group_id = ?", id).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return err
+	}
+	return recordRevision(tx, models.RevisionEntityGroup, id, models.RevisionActionUpdate, before, group.RevisionState())
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id uint, policy GroupDeletePolicy, targetID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var group models.Group
+		if err := tx.First(&group, id).Error; err != nil {
+			return translateError(err)
+		}
+
+		switch policy {
+		case GroupDeleteCascade:
+			var songs []models.Song
+			if err := tx.Where("group_id = ?", id).Find(&songs).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("group_id = ?", id).Delete(&models.Song{}).Error; err != nil {
+				return err
+			}
+			for _, song := range songs {
+				state := song.RevisionState(group.Name)
+				if err := recordRevision(tx, models.RevisionEntitySong, song.ID, models.RevisionActionDelete, state, state); err != nil {
+					return err
+				}
+			}
+		case GroupDeleteReassign:
+			var target models.Group
+			if err := tx.First(&target, targetID).Error; err != nil {
+				return translateError(err)
+			}
+			// Songs in the trash move too, so that restoring them later
+			// does not bring the merged group back.
+			var songs []models.Song
+			if err := tx.Unscoped().Where("group_id = ?", id).Find(&songs).Error; err != nil {
+				return err
+			}
+			err := tx.Unscoped().Model(&models.Song{}).Where("group_id = ?", id).Updates(map[string]any{
+				"group_id": targetID,
+				"version":  gorm.Expr("version + 1"),
+			}).Error
+			if err != nil {
+				return err
+			}
+			for _, song := range songs {
+				before := song.RevisionState(group.Name)
+				song.GroupId = targetID
+				if err := recordRevision(tx, models.RevisionEntitySong, song.ID, models.RevisionActionUpdate, before, song.RevisionState(target.Name)); err != nil {
+					return err
+				}
+			}
+		default:
+			var count int64
+			if err := tx.Model(&models.Song{}).Where("group_id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrGroupNotEmpty
+			}
+		}
+
+		if err := tx.Delete(&group).Error; err != nil {
+			return err
+		}
+		state := group.RevisionState()
+		return recordRevision(tx, models.RevisionEntityGroup, id, models.RevisionActionDelete, state, state)
+	})
+}
+
+func (r *groupRepository) Merge(ctx context.Context, sourceID, targetID uint) error {
+	return r.Delete(ctx, sourceID, GroupDeleteReassign, targetID)
+}
+
+func (r *groupRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var groups []models.Group
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Where("NOT EXISTS (SELECT 1 FROM songs WHERE songs.group_id = groups.id)").
+			Find(&groups).Error
+		if err != nil || len(groups) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(groups))
+		for i := range groups {
+			state := groups[i].RevisionState()
+			if err := recordRevision(tx, models.RevisionEntityGroup, groups[i].ID, models.RevisionActionPurge, state, state); err != nil {
+				return err
+			}
+			ids = append(ids, groups[i].ID)
+		}
+
+		result := tx.Unscoped().Delete(&models.Group{}, ids)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFirstOrCreateGroupConcurrently(t *testing.T) {
+	groups := NewGroupRepository(testDB(t))
+	name := fmt.Sprintf("Concurrent %d", time.Now().UnixNano())
+
+	const callers = 8
+	ids := make([]uint, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			group, err := groups.FirstOrCreate(context.Background(), name)
+			if err == nil {
+				ids[i] = group.ID
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("FirstOrCreate(%q) failed: %v", name, errs[i])
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("FirstOrCreate(%q) returned groups %d and %d", name, ids[0], ids[i])
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Muse", "Muse"},
+		{"100%", `100\%`},
+		{"AC_DC", `AC\_DC`},
+		{`back\slash`, `back\\slash`},
+		{`\%_`, `\\\%\_`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.value); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Enqueue(ctx context.Context, job *models.EnrichmentJob) error {
+	return enqueueJob(r.db.WithContext(ctx), job)
+}
+
+func enqueueJob(tx *gorm.DB, job *models.EnrichmentJob) error {
+	job.Status = models.JobStatusQueued
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	return tx.Create(job).Error
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uint) (*models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)",
+				models.JobStatusQueued, now, models.JobStatusRunning, staleBefore).
+			Order("run_at, id").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.JobStatusRunning
+		job.Attempts++
+		job.LockedAt = &now
+
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":    job.Status,
+			"attempts":  job.Attempts,
+			"locked_at": job.LockedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.EnrichmentJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     models.JobStatusDone,
+		"last_error": "",
+		"locked_at":  nil,
+	}).Error
+}
+
+func (r *jobRepository) Fail(ctx context.Context, id uint, lastError string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"status":     models.JobStatusFailed,
+		"last_error": lastError,
+		"locked_at":  nil,
+	}
+	if retryAt != nil {
+		updates["status"] = models.JobStatusQueued
+		updates["run_at"] = *retryAt
+	}
+	return r.db.WithContext(ctx).Model(&models.EnrichmentJob{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"sort"
+	"time"
+)
+
+type memoryGroupRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	group.Name = normalizeGroupName(group.Name)
+	if r.store.groupByName(group.Name) != nil {
+		return ErrConflict
+	}
+	r.store.insertGroup(ctx, group)
+	return nil
+}
+
+func (r *memoryGroupRepository) FirstOrCreate(ctx context.Context, name string) (*models.Group, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.firstOrCreateGroup(ctx, name), nil
+}
+
+func (r *memoryGroupRepository) GetByID(_ context.Context, id uint) (*models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group, ok := r.store.liveGroup(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &group, nil
+}
+
+func (r *memoryGroupRepository) GetByName(_ context.Context, name string) (*models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group := r.store.groupByName(normalizeGroupName(name))
+	if group == nil {
+		return nil, ErrNotFound
+	}
+	return group, nil
+}
+
+func (r *memoryGroupRepository) GetWithSongs(_ context.Context, id uint) (*models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group, ok := r.store.liveGroup(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	group.Songs = make([]models.Song, 0)
+	for _, song := range r.store.songs {
+		if song.GroupId == id && !song.DeletedAt.Valid {
+			song.GroupName = group.Name
+			group.Songs = append(group.Songs, song)
+		}
+	}
+	sort.Slice(group.Songs, func(i, j int) bool { return group.Songs[i].ID < group.Songs[j].ID })
+	return &group, nil
+}
+
+func (r *memoryGroupRepository) List(_ context.Context, filter GroupFilter) ([]models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	groups := r.store.filterGroups(filter)
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+
+	return paginate(groups, filter.Offset, filter.Limit), nil
+}
+
+func (r *memoryGroupRepository) Count(_ context.Context, filter GroupFilter) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.filterGroups(filter))), nil
+}
+
+// filterGroups returns the live groups matching the filter in no particular
+// order, ignoring pagination. It must be called with the store lock held.
+func (s *MemoryStore) filterGroups(filter GroupFilter) []models.Group {
+	groups := make([]models.Group, 0, len(s.groups))
+	for _, group := range s.groups {
+		if group.DeletedAt.Valid {
+			continue
+		}
+		if filter.Name != "" && !containsFold(group.Name, filter.Name) {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func (r *memoryGroupRepository) Rename(ctx context.Context, id uint, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.renameGroup(ctx, id, name)
+}
+
+func (r *memoryGroupRepository) Delete(ctx context.Context, id uint, policy GroupDeletePolicy, targetID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	group, ok := r.store.liveGroup(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+
+	switch policy {
+	case GroupDeleteCascade:
+		for songID, song := range r.store.songs {
+			if song.GroupId == id && !song.DeletedAt.Valid {
+				song.DeletedAt = deletedAt(now)
+				r.store.songs[songID] = song
+				state := song.RevisionState(group.Name)
+				r.store.recordRevision(ctx, models.RevisionEntitySong, songID, models.RevisionActionDelete, state, state)
+			}
+		}
+	case GroupDeleteReassign:
+		target, ok := r.store.liveGroup(targetID)
+		if !ok {
+			return ErrNotFound
+		}
+		for songID, song := range r.store.songs {
+			if song.GroupId == id {
+				before := song.RevisionState(group.Name)
+				song.GroupId = targetID
+				song.Version++
+				r.store.songs[songID] = song
+				r.store.recordRevision(ctx, models.RevisionEntitySong, songID, models.RevisionActionUpdate, before, song.RevisionState(target.Name))
+			}
+		}
+	default:
+		for _, song := range r.store.songs {
+			if song.GroupId == id && !song.DeletedAt.Valid {
+				return ErrGroupNotEmpty
+			}
+		}
+	}
+
+	group.DeletedAt = deletedAt(now)
+	r.store.groups[id] = group
+	state := group.RevisionState()
+	r.store.recordRevision(ctx, models.RevisionEntityGroup, id, models.RevisionActionDelete, state, state)
+	return nil
+}
+
+func (r *memoryGroupRepository) Merge(ctx context.Context, sourceID, targetID uint) error {
+	return r.Delete(ctx, sourceID, GroupDeleteReassign, targetID)
+}
+
+func (r *memoryGroupRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	referenced := make(map[uint]bool)
+	for _, song := range r.store.songs {
+		referenced[song.GroupId] = true
+	}
+
+	var purged int64
+	for id, group := range r.store.groups {
+		if group.DeletedAt.Valid && group.DeletedAt.Time.Before(deletedBefore) && !referenced[id] {
+			state := group.RevisionState()
+			r.store.recordRevision(ctx, models.RevisionEntityGroup, id, models.RevisionActionPurge, state, state)
+			delete(r.store.groups, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
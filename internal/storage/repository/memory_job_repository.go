@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"time"
+)
+
+type memoryJobRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryJobRepository) Enqueue(_ context.Context, job *models.EnrichmentJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.insertJob(job)
+	return nil
+}
+
+func (r *memoryJobRepository) GetByID(_ context.Context, id uint) (*models.EnrichmentJob, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+func (r *memoryJobRepository) ClaimNext(_ context.Context, staleBefore time.Time) (*models.EnrichmentJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var next *models.EnrichmentJob
+	for _, job := range r.store.jobs {
+		runnable := job.Status == models.JobStatusQueued && !job.RunAt.After(now)
+		stale := job.Status == models.JobStatusRunning && job.LockedAt != nil && job.LockedAt.Before(staleBefore)
+		if !runnable && !stale {
+			continue
+		}
+		if next == nil || job.RunAt.Before(next.RunAt) || (job.RunAt.Equal(next.RunAt) && job.ID < next.ID) {
+			candidate := job
+			next = &candidate
+		}
+	}
+	if next == nil {
+		return nil, ErrNotFound
+	}
+
+	next.Status = models.JobStatusRunning
+	next.Attempts++
+	next.LockedAt = &now
+	next.UpdatedAt = now
+	r.store.jobs[next.ID] = *next
+	return next, nil
+}
+
+func (r *memoryJobRepository) Complete(_ context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = models.JobStatusDone
+	job.LastError = ""
+	job.LockedAt = nil
+	job.UpdatedAt = time.Now()
+	r.store.jobs[id] = job
+	return nil
+}
+
+func (r *memoryJobRepository) Fail(_ context.Context, id uint, lastError string, retryAt *time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = models.JobStatusFailed
+	job.LastError = lastError
+	job.LockedAt = nil
+	if retryAt != nil {
+		job.Status = models.JobStatusQueued
+		job.RunAt = *retryAt
+	}
+	job.UpdatedAt = time.Now()
+	r.store.jobs[id] = job
+	return nil
+}
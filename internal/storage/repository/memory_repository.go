@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"gorm.io/gorm"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps songs, groups, enrichment jobs and revisions in process
+// memory. It is meant for tests and local runs where a Postgres instance is
+// not available.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	songs       map[uint]models.Song
+	sections    map[uint][]models.SongSection
+	synced      map[uint]models.SyncedLyrics
+	groups      map[uint]models.Group
+	jobs        map[uint]models.EnrichmentJob
+	revisions   []models.Revision
+	nextSongID  uint
+	nextGroupID uint
+	nextJobID   uint
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		songs:    make(map[uint]models.Song),
+		sections: make(map[uint][]models.SongSection),
+		synced:   make(map[uint]models.SyncedLyrics),
+		groups:   make(map[uint]models.Group),
+		jobs:     make(map[uint]models.EnrichmentJob),
+	}
+}
+
+func (s *MemoryStore) Songs() SongRepository {
+	return &memorySongRepository{store: s}
+}
+
+func (s *MemoryStore) Groups() GroupRepository {
+	return &memoryGroupRepository{store: s}
+}
+
+func (s *MemoryStore) Jobs() JobRepository {
+	return &memoryJobRepository{store: s}
+}
+
+func (s *MemoryStore) Revisions() RevisionRepository {
+	return &memoryRevisionRepository{store: s}
+}
+
+// insertSong must be called with the store lock held.
+func (s *MemoryStore) insertSong(ctx context.Context, song *models.Song) {
+	now := time.Now()
+	s.nextSongID++
+	song.ID = s.nextSongID
+	if song.Language == "" {
+		song.Language = models.DefaultLanguage
+	}
+	song.Version = 1
+	song.CreatedAt = now
+	song.UpdatedAt = now
+	s.songs[song.ID] = *song
+	s.sections[song.ID] = deriveSections(song)
+	s.recordRevision(ctx, models.RevisionEntitySong, song.ID, models.RevisionActionCreate, nil, s.songState(song))
+}
+
+// insertJob must be called with the store lock held.
+func (s *MemoryStore) insertJob(job *models.EnrichmentJob) {
+	now := time.Now()
+	s.nextJobID++
+	job.ID = s.nextJobID
+	job.Status = models.JobStatusQueued
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	s.jobs[job.ID] = *job
+}
+
+// insertGroup must be called with the store lock held.
+func (s *MemoryStore) insertGroup(ctx context.Context, group *models.Group) {
+	now := time.Now()
+	s.nextGroupID++
+	group.ID = s.nextGroupID
+	group.CreatedAt = now
+	group.UpdatedAt = now
+	s.groups[group.ID] = *group
+	s.recordRevision(ctx, models.RevisionEntityGroup, group.ID, models.RevisionActionCreate, nil, group.RevisionState())
+}
+
+// firstOrCreateGroup must be called with the store lock held.
+func (s *MemoryStore) firstOrCreateGroup(ctx context.Context, name string) *models.Group {
+	name = normalizeGroupName(name)
+	if existing := s.groupByName(name); existing != nil {
+		return existing
+	}
+	group := models.Group{Name: name}
+	s.insertGroup(ctx, &group)
+	return &group
+}
+
+// renameGroup must be called with the store lock held.
+func (s *MemoryStore) renameGroup(ctx context.Context, id uint, name string) error {
+	name = normalizeGroupName(name)
+	group, ok := s.liveGroup(id)
+	if !ok {
+		return ErrNotFound
+	}
+	if existing := s.groupByName(name); existing != nil && existing.ID != id {
+		return ErrConflict
+	}
+	before := group.RevisionState()
+	group.Name = name
+	group.UpdatedAt = time.Now()
+	s.groups[id] = group
+	for songID, song := range s.songs {
+		if song.GroupId == id {
+			song.Version++
+			s.songs[songID] = song
+		}
+	}
+	s.recordRevision(ctx, models.RevisionEntityGroup, id, models.RevisionActionUpdate, before, group.RevisionState())
+	return nil
+}
+
+// groupByName must be called with the store lock held. Deleted groups are
+// ignored.
+func (s *MemoryStore) groupByName(name string) *models.Group {
+	for _, group := range s.groups {
+		if group.Name == name && !group.DeletedAt.Valid {
+			return &group
+		}
+	}
+	return nil
+}
+
+// liveSong must be called with the store lock held.
+func (s *MemoryStore) liveSong(id uint) (models.Song, bool) {
+	song, ok := s.songs[id]
+	if !ok || song.DeletedAt.Valid {
+		return models.Song{}, false
+	}
+	return song, true
+}
+
+// liveGroup must be called with the store lock held.
+func (s *MemoryStore) liveGroup(id uint) (models.Group, bool) {
+	group, ok := s.groups[id]
+	if !ok || group.DeletedAt.Valid {
+		return models.Group{}, false
+	}
+	return group, true
+}
+
+func deletedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: true}
+}
+
+func containsFold(value, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
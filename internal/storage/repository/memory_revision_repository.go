@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"slices"
+	"time"
+)
+
+type memoryRevisionRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryRevisionRepository) List(_ context.Context, entityType string, entityID uint, offset, limit int) ([]models.Revision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	revisions := make([]models.Revision, 0)
+	for _, revision := range slices.Backward(r.store.revisions) {
+		if revision.EntityType == entityType && revision.EntityID == entityID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return paginate(revisions, offset, limit), nil
+}
+
+func (r *memoryRevisionRepository) GetByID(_ context.Context, id uint) (*models.Revision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// IDs start at 1 and are never reused, so they index the slice.
+	if id == 0 || int(id) > len(r.store.revisions) {
+		return nil, ErrNotFound
+	}
+	revision := r.store.revisions[id-1]
+	return &revision, nil
+}
+
+// recordRevision must be called with the store lock held.
+func (s *MemoryStore) recordRevision(ctx context.Context, entityType string, entityID uint, action string, before, after map[string]string) {
+	revision := newRevision(ctx, entityType, entityID, action, before, after)
+	if revision == nil {
+		return
+	}
+	revision.ID = uint(len(s.revisions) + 1)
+	revision.CreatedAt = time.Now()
+	s.revisions = append(s.revisions, *revision)
+}
+
+// songState must be called with the store lock held.
+func (s *MemoryStore) songState(song *models.Song) map[string]string {
+	return song.RevisionState(s.groups[song.GroupId].Name)
+}
@@ -0,0 +1,463 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"gorm.io/gorm"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type memorySongRepository struct {
+	store *MemoryStore
+}
+
+func (r *memorySongRepository) Create(ctx context.Context, song *models.Song) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.insertSong(ctx, song)
+	return nil
+}
+
+func (r *memorySongRepository) CreateWithJob(ctx context.Context, song *models.Song, job *models.EnrichmentJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.insertSong(ctx, song)
+	job.SongID = song.ID
+	r.store.insertJob(job)
+	return nil
+}
+
+func (r *memorySongRepository) QueueEnrichment(_ context.Context, job *models.EnrichmentJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	song, ok := r.store.liveSong(job.SongID)
+	if !ok {
+		return ErrNotFound
+	}
+	song.EnrichmentStatus = models.EnrichmentStatusPending
+	song.Version++
+	r.store.songs[song.ID] = song
+	r.store.insertJob(job)
+	return nil
+}
+
+func (r *memorySongRepository) GetByID(_ context.Context, id uint) (*models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	song, ok := r.store.liveSong(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &song, nil
+}
+
+func (r *memorySongRepository) GetByGroupAndTitle(_ context.Context, groupID uint, title string) (*models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, song := range r.store.songs {
+		if song.GroupId == groupID && song.Title == title && !song.DeletedAt.Valid {
+			return &song, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memorySongRepository) List(_ context.Context, filter SongFilter) ([]models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keys := filter.Sort
+	if len(keys) == 0 {
+		keys = DefaultSongSort
+	}
+
+	songs := r.store.filterSongs(filter)
+	sort.Slice(songs, func(i, j int) bool {
+		return compareSongs(songs[i], keys, sortPosition(songs[j], keys)) < 0
+	})
+
+	if filter.Cursor == nil {
+		return paginate(songs, filter.Offset, filter.Limit), nil
+	}
+
+	values, err := filter.Cursor.values(keys)
+	if err != nil {
+		return nil, err
+	}
+	position, found := slices.BinarySearchFunc(songs, values, func(song models.Song, values []any) int {
+		return compareSongs(song, keys, values)
+	})
+	if !filter.Cursor.Before {
+		if found {
+			position++
+		}
+		return paginate(songs, position, filter.Limit), nil
+	}
+
+	songs = songs[:position]
+	if filter.Limit > 0 && filter.Limit < len(songs) {
+		songs = songs[len(songs)-filter.Limit:]
+	}
+	return songs, nil
+}
+
+func (r *memorySongRepository) Count(_ context.Context, filter SongFilter) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.filterSongs(filter))), nil
+}
+
+// filterSongs returns the live songs matching the filter in no particular
+// order, ignoring pagination. It must be called with the store lock held.
+func (s *MemoryStore) filterSongs(filter SongFilter) []models.Song {
+	match := func(value, pattern string) bool {
+		if pattern == "" {
+			return true
+		}
+		if filter.Exact {
+			return value == pattern
+		}
+		return containsFold(value, pattern)
+	}
+
+	songs := make([]models.Song, 0, len(s.songs))
+	for _, song := range s.songs {
+		if song.DeletedAt.Valid {
+			continue
+		}
+		song.GroupName = s.groups[song.GroupId].Name
+
+		if !match(song.GroupName, filter.Group) || !match(song.Title, filter.Title) ||
+			!match(song.Text, filter.Text) || !match(song.Link, filter.Link) {
+			continue
+		}
+		if filter.GroupID != 0 && song.GroupId != filter.GroupID {
+			continue
+		}
+		if filter.ReleaseDate != nil && !song.ReleaseDate.Equal(*filter.ReleaseDate) {
+			continue
+		}
+		if !inRange(song.ReleaseDate, filter.ReleaseFrom, filter.ReleaseTo) ||
+			!inRange(song.CreatedAt, filter.CreatedFrom, filter.CreatedTo) ||
+			!inRange(song.UpdatedAt, filter.UpdatedFrom, filter.UpdatedTo) {
+			continue
+		}
+		songs = append(songs, song)
+	}
+	return songs
+}
+
+func inRange(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+}
+
+// Search only approximates the Postgres implementation: words are compared
+// as they are, without stemming or stop words.
+func (r *memorySongRepository) Search(_ context.Context, query SearchQuery) ([]SearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	terms := SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return []SearchResult{}, nil
+	}
+	match := func(word string) bool {
+		for _, term := range terms {
+			if word == term || (query.Mode == SearchModePrefix && strings.HasPrefix(word, term)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	results := make([]SearchResult, 0)
+	for _, song := range r.store.songs {
+		if song.DeletedAt.Valid {
+			continue
+		}
+		if query.Language != "" && song.Language != query.Language {
+			continue
+		}
+		words := append(SearchTerms(song.Title), SearchTerms(song.Text)...)
+		if !memorySearchMatches(words, terms, query.Mode) {
+			continue
+		}
+
+		title, titleHits := highlightWords(song.Title, match)
+		text, textHits := highlightWords(song.Text, match)
+		song.GroupName = r.store.groups[song.GroupId].Name
+		results = append(results, SearchResult{
+			Song:             song,
+			Rank:             float64(titleHits) + 0.4*float64(textHits),
+			HighlightedTitle: title,
+			HighlightedText:  text,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Song.ID < results[j].Song.ID
+	})
+
+	return paginate(results, query.Offset, query.Limit), nil
+}
+
+func memorySearchMatches(words, terms []string, mode SearchMode) bool {
+	if mode == SearchModePhrase {
+		for i := 0; i+len(terms) <= len(words); i++ {
+			if slices.Equal(words[i:i+len(terms)], terms) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, term := range terms {
+		found := slices.ContainsFunc(words, func(word string) bool {
+			return word == term || (mode == SearchModePrefix && strings.HasPrefix(word, term))
+		})
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightWords wraps every word accepted by match in the highlight markers
+// and reports how many words were wrapped.
+func highlightWords(value string, match func(word string) bool) (string, int) {
+	var b strings.Builder
+	hits := 0
+	start := -1
+	flush := func(end int) {
+		word := value[start:end]
+		if match(strings.ToLower(word)) {
+			b.WriteString(HighlightStart + word + HighlightStop)
+			hits++
+		} else {
+			b.WriteString(word)
+		}
+		start = -1
+	}
+
+	for i, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			flush(i)
+		}
+		b.WriteRune(r)
+	}
+	if start >= 0 {
+		flush(len(value))
+	}
+	return b.String(), hits
+}
+
+func (r *memorySongRepository) Update(ctx context.Context, song *models.Song) error {
+	return r.UpdateWithGroup(ctx, song, "", SongGroupRename)
+}
+
+func (r *memorySongRepository) UpdateWithGroup(ctx context.Context, song *models.Song, groupName string, mode SongGroupMode) error {
+	groupName = normalizeGroupName(groupName)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	previous, ok := r.store.liveSong(song.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if previous.Version != song.Version {
+		return ErrVersionMismatch
+	}
+	before := r.store.songState(&previous)
+
+	if groupName != "" {
+		if group, ok := r.store.liveGroup(song.GroupId); ok && mode == SongGroupRestore {
+			song.GroupName = group.Name
+		} else if mode == SongGroupRename {
+			if err := r.store.renameGroup(ctx, song.GroupId, groupName); errors.Is(err, ErrNotFound) {
+				return ErrGroupNotFound
+			} else if err != nil {
+				return err
+			}
+			song.GroupName = groupName
+		} else {
+			song.GroupId = r.store.firstOrCreateGroup(ctx, groupName).ID
+			song.GroupName = groupName
+		}
+	}
+
+	if previous.Text != song.Text {
+		delete(r.store.synced, song.ID)
+	}
+	song.Version++
+	song.UpdatedAt = time.Now()
+	r.store.songs[song.ID] = *song
+	r.store.sections[song.ID] = deriveSections(song)
+	r.store.recordRevision(ctx, models.RevisionEntitySong, song.ID, models.RevisionActionUpdate, before, r.store.songState(song))
+	return nil
+}
+
+func (r *memorySongRepository) Sections(_ context.Context, songID uint) ([]models.SongSection, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(r.store.sections[songID]), nil
+}
+
+func (r *memorySongRepository) SyncedLyrics(_ context.Context, songID uint) (*models.SyncedLyrics, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	lyrics, ok := r.store.synced[songID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &lyrics, nil
+}
+
+func (r *memorySongRepository) SaveSyncedLyrics(ctx context.Context, song *models.Song, lyrics *models.SyncedLyrics) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	previous, ok := r.store.liveSong(song.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if previous.Version != song.Version {
+		return ErrVersionMismatch
+	}
+
+	now := time.Now()
+	song.Version++
+	song.UpdatedAt = now
+	r.store.songs[song.ID] = *song
+	r.store.sections[song.ID] = deriveSections(song)
+
+	lyrics.SongID = song.ID
+	lyrics.CreatedAt = now
+	if previous, ok := r.store.synced[song.ID]; ok {
+		lyrics.CreatedAt = previous.CreatedAt
+	}
+	lyrics.UpdatedAt = now
+	r.store.synced[song.ID] = *lyrics
+	r.store.recordRevision(ctx, models.RevisionEntitySong, song.ID, models.RevisionActionUpdate, r.store.songState(&previous), r.store.songState(song))
+	return nil
+}
+
+func (r *memorySongRepository) Delete(ctx context.Context, id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	song, ok := r.store.liveSong(id)
+	if !ok {
+		return ErrNotFound
+	}
+	if version != 0 && song.Version != version {
+		return ErrVersionMismatch
+	}
+	song.DeletedAt = deletedAt(time.Now())
+	r.store.songs[id] = song
+	state := r.store.songState(&song)
+	r.store.recordRevision(ctx, models.RevisionEntitySong, id, models.RevisionActionDelete, state, state)
+	return nil
+}
+
+func (r *memorySongRepository) ListDeleted(_ context.Context, offset, limit int) ([]models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	songs := make([]models.Song, 0)
+	for _, song := range r.store.songs {
+		if !song.DeletedAt.Valid {
+			continue
+		}
+		song.GroupName = r.store.groups[song.GroupId].Name
+		songs = append(songs, song)
+	}
+
+	sort.Slice(songs, func(i, j int) bool {
+		if !songs[i].DeletedAt.Time.Equal(songs[j].DeletedAt.Time) {
+			return songs[i].DeletedAt.Time.After(songs[j].DeletedAt.Time)
+		}
+		return songs[i].ID < songs[j].ID
+	})
+
+	return paginate(songs, offset, limit), nil
+}
+
+func (r *memorySongRepository) Restore(ctx context.Context, id uint) (*models.Song, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	song, ok := r.store.songs[id]
+	if !ok || !song.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	group, ok := r.store.groups[song.GroupId]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if group.DeletedAt.Valid {
+		if live := r.store.groupByName(group.Name); live != nil {
+			song.GroupId = live.ID
+		} else {
+			group.DeletedAt = gorm.DeletedAt{}
+			r.store.groups[group.ID] = group
+			state := group.RevisionState()
+			r.store.recordRevision(ctx, models.RevisionEntityGroup, group.ID, models.RevisionActionRestore, state, state)
+		}
+	}
+
+	song.DeletedAt = gorm.DeletedAt{}
+	song.Version++
+	r.store.songs[id] = song
+	state := song.RevisionState(group.Name)
+	r.store.recordRevision(ctx, models.RevisionEntitySong, id, models.RevisionActionRestore, state, state)
+
+	song.GroupName = group.Name
+	return &song, nil
+}
+
+func (r *memorySongRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, song := range r.store.songs {
+		if song.DeletedAt.Valid && song.DeletedAt.Time.Before(deletedBefore) {
+			state := r.store.songState(&song)
+			r.store.recordRevision(ctx, models.RevisionEntitySong, id, models.RevisionActionPurge, state, state)
+			delete(r.store.songs, id)
+			delete(r.store.sections, id)
+			delete(r.store.synced, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
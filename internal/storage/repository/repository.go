@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("record not found")
+	ErrConflict      = errors.New("record already exists")
+	ErrGroupNotEmpty = errors.New("group still has songs")
+	ErrInvalidCursor = errors.New("cursor does not match the sort order")
+	// ErrGroupNotFound means the group to rename with a song is gone,
+	// while the song itself still exists.
+	ErrGroupNotFound = errors.New("group of the song not found")
+	// ErrVersionMismatch means the record changed since the caller read it.
+	ErrVersionMismatch = errors.New("record version does not match")
+)
+
+// GroupDeletePolicy decides what happens to the songs of a deleted group.
+type GroupDeletePolicy string
+
+const (
+	// GroupDeleteRestrict refuses to delete a group that still has songs.
+	GroupDeleteRestrict GroupDeletePolicy = "restrict"
+	// GroupDeleteCascade deletes the songs together with the group.
+	GroupDeleteCascade GroupDeletePolicy = "cascade"
+	// GroupDeleteReassign moves the songs to another group first.
+	GroupDeleteReassign GroupDeletePolicy = "reassign"
+)
+
+// SongGroupMode decides how UpdateWithGroup gives a song a new group name.
+type SongGroupMode string
+
+const (
+	// SongGroupRename renames the group of the song, and so of all its
+	// songs.
+	SongGroupRename SongGroupMode = "rename"
+	// SongGroupReassign moves just the song to the group with the name,
+	// which is created when there is none.
+	SongGroupReassign SongGroupMode = "reassign"
+	// SongGroupRestore puts the song back in the group with its GroupId,
+	// whatever that group is called now, and falls back to reassigning by
+	// name when that group is gone.
+	SongGroupRestore SongGroupMode = "restore"
+)
+
+type GroupFilter struct {
+	Name   string
+	Offset int
+	Limit  int
+}
+
+type SongFilter struct {
+	Group       string
+	GroupID     uint
+	Title       string
+	ReleaseDate *time.Time
+	// ReleaseFrom and ReleaseTo bound the release date, both inclusive.
+	ReleaseFrom *time.Time
+	ReleaseTo   *time.Time
+	Text        string
+	Link        string
+	// Exact compares Group, Title, Text and Link as a whole instead of
+	// looking for a case-insensitive substring.
+	Exact       bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// Sort defaults to DefaultSongSort. It must end with the id, as
+	// returned by ParseSongSort.
+	Sort []SongSort
+	// Cursor switches to keyset pagination: songs are taken from right after
+	// (or right before) the cursor and Offset is ignored.
+	Cursor *SongCursor
+	Offset int
+	Limit  int
+}
+
+// SongCursor points at a song of a listing, see NewSongCursor.
+type SongCursor struct {
+	// Values holds the sort key of the song, one value per sort field
+	// except the trailing id.
+	Values []string
+	ID     uint
+	// Before selects the songs preceding the cursor instead of the ones
+	// following it. They are still returned in listing order.
+	Before bool
+}
+
+type SearchMode string
+
+const (
+	// SearchModeWeb accepts web search syntax: "quoted phrases", or, -word.
+	SearchModeWeb SearchMode = "web"
+	// SearchModePhrase matches the words next to each other in that order.
+	SearchModePhrase SearchMode = "phrase"
+	// SearchModePrefix matches words starting with each of the given words.
+	SearchModePrefix SearchMode = "prefix"
+)
+
+// Matches in SearchResult highlights are wrapped in these markers.
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+type SearchQuery struct {
+	Text string
+	Mode SearchMode
+	// Language restricts the search to songs with this text search
+	// configuration and parses the query with it. When empty, every song is
+	// matched against a query parsed with models.DefaultLanguage.
+	Language string
+	Offset   int
+	Limit    int
+}
+
+type SearchResult struct {
+	Song             models.Song
+	Rank             float64
+	HighlightedTitle string
+	HighlightedText  string
+}
+
+type SongRepository interface {
+	Create(ctx context.Context, song *models.Song) error
+	// CreateWithJob creates the song and enqueues the enrichment job for it
+	// in one transaction, so that a song is never left waiting for a job
+	// that was not queued.
+	CreateWithJob(ctx context.Context, song *models.Song, job *models.EnrichmentJob) error
+	// QueueEnrichment marks the song of the job as pending enrichment and
+	// enqueues the job in one transaction, so that the song never reports
+	// an old status while the job waits. It fails with ErrNotFound when the
+	// song is gone.
+	QueueEnrichment(ctx context.Context, job *models.EnrichmentJob) error
+	GetByID(ctx context.Context, id uint) (*models.Song, error)
+	GetByGroupAndTitle(ctx context.Context, groupID uint, title string) (*models.Song, error)
+	List(ctx context.Context, filter SongFilter) ([]models.Song, error)
+	// Count returns the number of songs matching the filter, ignoring
+	// pagination.
+	Count(ctx context.Context, filter SongFilter) (int64, error)
+	// Search runs a full-text search over titles and lyrics and returns the
+	// matching songs ordered by relevance.
+	Search(ctx context.Context, query SearchQuery) ([]SearchResult, error)
+	// Update saves the song. Create and Update also store the sections of
+	// the song text. Synced lyrics are dropped when the text changes.
+	//
+	// Update and SaveSyncedLyrics fail with ErrVersionMismatch unless the
+	// stored song still has the version of the given one, and increment the
+	// version on success.
+	Update(ctx context.Context, song *models.Song) error
+	// UpdateWithGroup updates the song like Update and, in the same
+	// transaction, gives it the group named groupName as mode says; an empty
+	// groupName leaves the group as it is. Renaming the group to the name of
+	// another group fails with ErrConflict, and renaming a group deleted
+	// in the meantime with ErrGroupNotFound.
+	UpdateWithGroup(ctx context.Context, song *models.Song, groupName string, mode SongGroupMode) error
+	// Sections returns the sections of the song text in order.
+	Sections(ctx context.Context, songID uint) ([]models.SongSection, error)
+	// SyncedLyrics returns ErrNotFound when the song has none.
+	SyncedLyrics(ctx context.Context, songID uint) (*models.SyncedLyrics, error)
+	// SaveSyncedLyrics saves the song together with its synced lyrics. The
+	// song text must hold the same lines.
+	SaveSyncedLyrics(ctx context.Context, song *models.Song, lyrics *models.SyncedLyrics) error
+	// Delete moves the song to the trash. It fails with ErrVersionMismatch
+	// when version is not zero and differs from the stored one.
+	Delete(ctx context.Context, id uint, version uint) error
+	ListDeleted(ctx context.Context, offset, limit int) ([]models.Song, error)
+	// Restore takes the song out of the trash. A deleted group of the song is
+	// restored as well, unless another group with the same name exists by
+	// now, in which case the song is moved to that group.
+	Restore(ctx context.Context, id uint) (*models.Song, error)
+	// Purge permanently removes songs deleted before the given time.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// GroupRepository stores groups. Group names given to it, including the
+// one of UpdateWithGroup, are trimmed of surrounding white space, so that
+// "Muse " and "Muse" are the same group.
+type GroupRepository interface {
+	Create(ctx context.Context, group *models.Group) error
+	FirstOrCreate(ctx context.Context, name string) (*models.Group, error)
+	GetByID(ctx context.Context, id uint) (*models.Group, error)
+	GetByName(ctx context.Context, name string) (*models.Group, error)
+	GetWithSongs(ctx context.Context, id uint) (*models.Group, error)
+	List(ctx context.Context, filter GroupFilter) ([]models.Group, error)
+	// Count returns the number of groups matching the filter, ignoring its
+	// offset and limit.
+	Count(ctx context.Context, filter GroupFilter) (int64, error)
+	Rename(ctx context.Context, id uint, name string) error
+	// Delete removes the group; targetID is only used by GroupDeleteReassign.
+	Delete(ctx context.Context, id uint, policy GroupDeletePolicy, targetID uint) error
+	// Merge moves every song of the source group to the target group and
+	// deletes the source group.
+	Merge(ctx context.Context, sourceID, targetID uint) error
+	// Purge permanently removes groups deleted before the given time that
+	// no song refers to anymore.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// RevisionRepository reads the revision history. Revisions are written by
+// the song and group repositories along with the changes they record.
+type RevisionRepository interface {
+	// List returns the revisions of a song or group, newest first.
+	List(ctx context.Context, entityType string, entityID uint, offset, limit int) ([]models.Revision, error)
+	GetByID(ctx context.Context, id uint) (*models.Revision, error)
+}
+
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *models.EnrichmentJob) error
+	GetByID(ctx context.Context, id uint) (*models.EnrichmentJob, error)
+	// ClaimNext marks the oldest runnable job as running and returns it.
+	// Running jobs locked before staleBefore are considered abandoned and
+	// may be claimed again. ErrNotFound is returned when there is no work.
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*models.EnrichmentJob, error)
+	Complete(ctx context.Context, id uint) error
+	// Fail records the error of the last attempt. With a non-nil retryAt the
+	// job is queued again, otherwise it is marked as failed for good.
+	Fail(ctx context.Context, id uint, lastError string, retryAt *time.Time) error
+}
+
+// PurgeTrash permanently removes songs and then groups deleted before the
+// given time. Groups still referenced by a song are kept.
+func PurgeTrash(ctx context.Context, songs SongRepository, groups GroupRepository, deletedBefore time.Time) (int64, int64, error) {
+	purgedSongs, err := songs.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	purgedGroups, err := groups.Purge(ctx, deletedBefore)
+	if err != nil {
+		return purgedSongs, 0, err
+	}
+
+	if purgedSongs > 0 || purgedGroups > 0 {
+		logger.Info("trash purged", slog.Int64("songs", purgedSongs), slog.Int64("groups", purgedGroups))
+	}
+	return purgedSongs, purgedGroups, nil
+}
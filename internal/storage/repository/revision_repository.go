@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/audit"
+	"effectiveMobileTask/internal/models"
+	"gorm.io/gorm"
+)
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+func (r *revisionRepository) List(ctx context.Context, entityType string, entityID uint, offset, limit int) ([]models.Revision, error) {
+	var revisions []models.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *revisionRepository) GetByID(ctx context.Context, id uint) (*models.Revision, error) {
+	var revision models.Revision
+	if err := r.db.WithContext(ctx).First(&revision, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &revision, nil
+}
+
+// newRevision describes a change made by the actor of ctx. It returns nil
+// for updates that leave the tracked fields as they are.
+func newRevision(ctx context.Context, entityType string, entityID uint, action string, before, after map[string]string) *models.Revision {
+	changes := models.DiffStates(before, after)
+	if action == models.RevisionActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	revision := &models.Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      audit.Actor(ctx),
+		Changes:    changes,
+		State:      after,
+	}
+	if revertOf := audit.RevertOf(ctx); revertOf != 0 && entityType == models.RevisionEntitySong && action == models.RevisionActionUpdate {
+		revision.Action = models.RevisionActionRevert
+		revision.RevertOf = &revertOf
+	}
+	return revision
+}
+
+// recordRevision stores a revision in the transaction tx.
+func recordRevision(tx *gorm.DB, entityType string, entityID uint, action string, before, after map[string]string) error {
+	revision := newRevision(tx.Statement.Context, entityType, entityID, action, before, after)
+	if revision == nil {
+		return nil
+	}
+	return tx.Create(revision).Error
+}
+
+// songState returns the revision state of the song, looking up the name of
+// its group in tx.
+func songState(tx *gorm.DB, song *models.Song) (map[string]string, error) {
+	var name string
+	if err := tx.Unscoped().Model(&models.Group{}).Where("id = ?", song.GroupId).Pluck("name", &name).Error; err != nil {
+		return nil, err
+	}
+	return song.RevisionState(name), nil
+}
+
+// recordSongRevision stores a revision of the song. before may be nil, as
+// for a new song.
+func recordSongRevision(tx *gorm.DB, song *models.Song, action string, before map[string]string) error {
+	after, err := songState(tx, song)
+	if err != nil {
+		return err
+	}
+	return recordRevision(tx, models.RevisionEntitySong, song.ID, action, before, after)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPurgeRecordsRevisions(t *testing.T) {
+	db := testDB(t)
+	songs, groups, revisions := NewSongRepository(db), NewGroupRepository(db), NewRevisionRepository(db)
+	ctx := context.Background()
+
+	group, err := groups.FirstOrCreate(ctx, fmt.Sprintf("Purged %d", time.Now().UnixNano()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	song := &models.Song{GroupId: group.ID, Title: "Purged"}
+	if err := songs.Create(ctx, song); err != nil {
+		t.Fatal(err)
+	}
+	if err := songs.Delete(ctx, song.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.Delete(ctx, group.ID, GroupDeleteRestrict, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := PurgeTrash(ctx, songs, groups, time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entity := range []struct {
+		kind string
+		id   uint
+	}{{models.RevisionEntitySong, song.ID}, {models.RevisionEntityGroup, group.ID}} {
+		latest, err := revisions.List(ctx, entity.kind, entity.id, 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(latest) != 1 || latest[0].Action != models.RevisionActionPurge {
+			t.Errorf("latest revision of %s %d = %+v, want a purge", entity.kind, entity.id, latest)
+		}
+	}
+}
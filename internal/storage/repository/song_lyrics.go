@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/lyrics"
+	"effectiveMobileTask/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *songRepository) Sections(ctx context.Context, songID uint) ([]models.SongSection, error) {
+	song, err := r.GetByID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []models.SongSection
+	if err := r.db.WithContext(ctx).Where("song_id = ?", songID).Order("position").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+
+	// Songs written before sections were introduced have none stored yet.
+	if len(sections) == 0 && song.Text != "" {
+		sections = deriveSections(song)
+		if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&sections).Error; err != nil {
+			return nil, err
+		}
+	}
+	return sections, nil
+}
+
+// replaceSections stores the sections of the current song text in place of
+// the previous ones.
+func replaceSections(tx *gorm.DB, song *models.Song) error {
+	if err := tx.Where("song_id = ?", song.ID).Delete(&models.SongSection{}).Error; err != nil {
+		return err
+	}
+
+	sections := deriveSections(song)
+	if len(sections) == 0 {
+		return nil
+	}
+	return tx.Create(&sections).Error
+}
+
+func deriveSections(song *models.Song) []models.SongSection {
+	parsed := lyrics.Parse(song.Text)
+
+	sections := make([]models.SongSection, len(parsed))
+	for i, section := range parsed {
+		sections[i] = models.SongSection{
+			SongID:    song.ID,
+			Position:  i + 1,
+			Type:      section.Type,
+			Ordinal:   section.Ordinal,
+			Header:    section.Header,
+			FirstLine: section.FirstLine,
+			Lines:     section.Lines,
+		}
+	}
+	return sections
+}
+
+func (r *songRepository) SyncedLyrics(ctx context.Context, songID uint) (*models.SyncedLyrics, error) {
+	var lyrics models.SyncedLyrics
+	if err := r.db.WithContext(ctx).First(&lyrics, songID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &lyrics, nil
+}
+
+func (r *songRepository) SaveSyncedLyrics(ctx context.Context, song *models.Song, lyrics *models.SyncedLyrics) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := lockVersion(tx, song)
+		if err != nil {
+			return err
+		}
+		before, err := songState(tx, stored)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Save(song).Error; err != nil {
+			return err
+		}
+		if err := replaceSections(tx, song); err != nil {
+			return err
+		}
+
+		lyrics.SongID = song.ID
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "song_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tags", "lines", "updated_at"}),
+		}).Create(lyrics).Error
+		if err != nil {
+			return err
+		}
+		return recordSongRevision(tx, song, models.RevisionActionUpdate, before)
+	})
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"slices"
+	"time"
+)
+
+type songRepository struct {
+	db *gorm.DB
+}
+
+func NewSongRepository(db *gorm.DB) SongRepository {
+	return &songRepository{db: db}
+}
+
+func (r *songRepository) Create(ctx context.Context, song *models.Song) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(song).Error; err != nil {
+			return err
+		}
+		if err := replaceSections(tx, song); err != nil {
+			return err
+		}
+		return recordSongRevision(tx, song, models.RevisionActionCreate, nil)
+	})
+}
+
+func (r *songRepository) CreateWithJob(ctx context.Context, song *models.Song, job *models.EnrichmentJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(song).Error; err != nil {
+			return err
+		}
+		if err := replaceSections(tx, song); err != nil {
+			return err
+		}
+		if err := recordSongRevision(tx, song, models.RevisionActionCreate, nil); err != nil {
+			return err
+		}
+		job.SongID = song.ID
+		return enqueueJob(tx, job)
+	})
+}
+
+func (r *songRepository) QueueEnrichment(ctx context.Context, job *models.EnrichmentJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The status is part of the song, so its ETag changes as well.
+		result := tx.Model(&models.Song{}).Where("id = ?", job.SongID).Updates(map[string]interface{}{
+			"enrichment_status": models.EnrichmentStatusPending,
+			"version":           gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return enqueueJob(tx, job)
+	})
+}
+
+func (r *songRepository) GetByID(ctx context.Context, id uint) (*models.Song, error) {
+	var song models.Song
+	if err := r.db.WithContext(ctx).First(&song, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &song, nil
+}
+
+func (r *songRepository) GetByGroupAndTitle(ctx context.Context, groupID uint, title string) (*models.Song, error) {
+	var song models.Song
+	if err := r.db.WithContext(ctx).Where("group_id = ? AND title = ?", groupID, title).First(&song).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &song, nil
+}
+
+func (r *songRepository) List(ctx context.Context, filter SongFilter) ([]models.Song, error) {
+	var songs []models.Song
+
+	keys := filter.Sort
+	if len(keys) == 0 {
+		keys = DefaultSongSort
+	}
+
+	query := r.filtered(ctx, filter).Select("songs.*, groups.name AS group_name")
+
+	before := false
+	if filter.Cursor == nil {
+		query = query.Offset(filter.Offset)
+	} else {
+		values, err := filter.Cursor.values(keys)
+		if err != nil {
+			return nil, err
+		}
+		before = filter.Cursor.Before
+		condition, args := keysetCondition(keys, values, before)
+		query = query.Where(condition, args...)
+	}
+
+	if err := query.Order(orderClause(keys, before)).Limit(filter.Limit).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+
+	if before {
+		slices.Reverse(songs)
+	}
+	return songs, nil
+}
+
+func (r *songRepository) Count(ctx context.Context, filter SongFilter) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// filtered applies the filter conditions, but not the order and pagination.
+func (r *songRepository) filtered(ctx context.Context, filter SongFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Song{}).
+		Joins("JOIN groups ON songs.group_id = groups.id")
+
+	match := func(column, value string) {
+		if filter.Exact {
+			query = query.Where(column+" = ?", value)
+		} else {
+			query = query.Where(column+" ILIKE ?", "%"+escapeLike(value)+"%")
+		}
+	}
+
+	if filter.Group != "" {
+		match("groups.name", filter.Group)
+	}
+
+	if filter.GroupID != 0 {
+		query = query.Where("songs.group_id = ?", filter.GroupID)
+	}
+
+	if filter.Title != "" {
+		match("songs.title", filter.Title)
+	}
+
+	if filter.ReleaseDate != nil {
+		query = query.Where("songs.release_date = ?", *filter.ReleaseDate)
+	}
+
+	if filter.ReleaseFrom != nil {
+		query = query.Where("songs.release_date >= ?", *filter.ReleaseFrom)
+	}
+
+	if filter.ReleaseTo != nil {
+		query = query.Where("songs.release_date <= ?", *filter.ReleaseTo)
+	}
+
+	if filter.Text != "" {
+		match("songs.text", filter.Text)
+	}
+
+	if filter.Link != "" {
+		match("songs.link", filter.Link)
+	}
+
+	if filter.CreatedFrom != nil {
+		query = query.Where("songs.created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		query = query.Where("songs.created_at <= ?", *filter.CreatedTo)
+	}
+
+	if filter.UpdatedFrom != nil {
+		query = query.Where("songs.updated_at >= ?", *filter.UpdatedFrom)
+	}
+
+	if filter.UpdatedTo != nil {
+		query = query.Where("songs.updated_at <= ?", *filter.UpdatedTo)
+	}
+
+	return query
+}
+
+func (r *songRepository) Update(ctx context.Context, song *models.Song) error {
+	return r.UpdateWithGroup(ctx, song, "", SongGroupRename)
+}
+
+func (r *songRepository) UpdateWithGroup(ctx context.Context, song *models.Song, groupName string, mode SongGroupMode) error {
+	groupName = normalizeGroupName(groupName)
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := lockVersion(tx, song)
+		if err != nil {
+			return err
+		}
+		before, err := songState(tx, stored)
+		if err != nil {
+			return err
+		}
+
+		if groupName != "" {
+			if err := applySongGroup(tx, song, groupName, mode); err != nil {
+				return err
+			}
+		}
+
+		if stored.Text != song.Text {
+			if err := tx.Delete(&models.SyncedLyrics{}, song.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(song).Error; err != nil {
+			return err
+		}
+		if err := replaceSections(tx, song); err != nil {
+			return err
+		}
+		return recordSongRevision(tx, song, models.RevisionActionUpdate, before)
+	}))
+}
+
+// applySongGroup gives the song the group named groupName as mode says.
+func applySongGroup(tx *gorm.DB, song *models.Song, groupName string, mode SongGroupMode) error {
+	if mode == SongGroupRestore {
+		var group models.Group
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&group, song.GroupId).Error
+		if err == nil {
+			song.GroupName = group.Name
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		mode = SongGroupReassign
+	}
+
+	if mode == SongGroupReassign {
+		group, err := firstOrCreateGroup(tx, groupName)
+		if err != nil {
+			return err
+		}
+		song.GroupId = group.ID
+	} else if err := renameGroup(tx, song.GroupId, groupName); errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrGroupNotFound
+	} else if err != nil {
+		return err
+	}
+	song.GroupName = groupName
+	return nil
+}
+
+// lockVersion locks the row of the song until the end of the transaction,
+// makes sure it still has the version of the song and increments the
+// version of the song. It returns the stored song.
+func lockVersion(tx *gorm.DB, song *models.Song) (*models.Song, error) {
+	var stored models.Song
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, song.ID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	if stored.Version != song.Version {
+		return nil, ErrVersionMismatch
+	}
+	song.Version++
+	return &stored, nil
+}
+
+func (r *songRepository) Delete(ctx context.Context, id uint, version uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var song models.Song
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&song, id).Error; err != nil {
+			return translateError(err)
+		}
+		if version != 0 && song.Version != version {
+			return ErrVersionMismatch
+		}
+
+		if err := tx.Delete(&song).Error; err != nil {
+			return err
+		}
+		state, err := songState(tx, &song)
+		if err != nil {
+			return err
+		}
+		return recordRevision(tx, models.RevisionEntitySong, id, models.RevisionActionDelete, state, state)
+	})
+}
+
+func (r *songRepository) ListDeleted(ctx context.Context, offset, limit int) ([]models.Song, error) {
+	var songs []models.Song
+
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Song{}).
+		Select("songs.*, groups.name AS group_name").
+		Joins("JOIN groups ON songs.group_id = groups.id").
+		Where("songs.deleted_at IS NOT NULL").
+		Order("songs.deleted_at DESC, songs.id").
+		Offset(offset).Limit(limit).
+		Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+func (r *songRepository) Restore(ctx context.Context, id uint) (*models.Song, error) {
+	var song models.Song
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&song, id).Error; err != nil {
+			return err
+		}
+
+		var group models.Group
+		if err := tx.Unscoped().First(&group, song.GroupId).Error; err != nil {
+			return err
+		}
+
+		if group.DeletedAt.Valid {
+			var live models.Group
+			err := tx.Where("name = ?", group.Name).First(&live).Error
+			switch {
+			case err == nil:
+				song.GroupId = live.ID
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Unscoped().Model(&group).Update("deleted_at", nil).Error; err != nil {
+					return err
+				}
+				state := group.RevisionState()
+				if err := recordRevision(tx, models.RevisionEntityGroup, group.ID, models.RevisionActionRestore, state, state); err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+		}
+
+		// The restored song is served again, so an ETag taken before it was
+		// deleted must not match it any more.
+		song.GroupName = group.Name
+		song.DeletedAt = gorm.DeletedAt{}
+		song.Version++
+		err := tx.Unscoped().Model(&song).Updates(map[string]interface{}{
+			"group_id":   song.GroupId,
+			"deleted_at": nil,
+			"version":    song.Version,
+		}).Error
+		if err != nil {
+			return err
+		}
+		state := song.RevisionState(group.Name)
+		return recordRevision(tx, models.RevisionEntitySong, song.ID, models.RevisionActionRestore, state, state)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &song, nil
+}
+
+func (r *songRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var songs []models.Song
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Find(&songs).Error
+		if err != nil || len(songs) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(songs))
+		for i := range songs {
+			state, err := songState(tx, &songs[i])
+			if err != nil {
+				return err
+			}
+			if err := recordRevision(tx, models.RevisionEntitySong, songs[i].ID, models.RevisionActionPurge, state, state); err != nil {
+				return err
+			}
+			ids = append(ids, songs[i].ID)
+		}
+
+		result := tx.Unscoped().Delete(&models.Song{}, ids)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrConflict
+	}
+	return err
+}
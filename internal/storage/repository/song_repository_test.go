@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+// testUpdateWithDeletedGroup checks UpdateWithGroup on a song whose group
+// deleteGroup deletes behind the back of the repository.
+func testUpdateWithDeletedGroup(t *testing.T, songs SongRepository, groups GroupRepository, deleteGroup func(id uint)) {
+	t.Helper()
+	ctx := context.Background()
+	name := fmt.Sprintf("Deleted %d", time.Now().UnixNano())
+	group, err := groups.FirstOrCreate(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	song := &models.Song{GroupId: group.ID, Title: "Orphan"}
+	if err := songs.Create(ctx, song); err != nil {
+		t.Fatal(err)
+	}
+	deleteGroup(group.ID)
+
+	song, err = songs.GetByID(ctx, song.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamed := *song
+	if err := songs.UpdateWithGroup(ctx, &renamed, name+" renamed", SongGroupRename); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("renaming a deleted group: error = %v, want ErrGroupNotFound", err)
+	}
+	if err := songs.UpdateWithGroup(ctx, song, name+" reassigned", SongGroupReassign); err != nil {
+		t.Errorf("reassigning from a deleted group: %v", err)
+	}
+
+	missing := &models.Song{ID: song.ID + 1000, GroupId: group.ID, Title: "Missing"}
+	if err := songs.UpdateWithGroup(ctx, missing, name, SongGroupRename); !errors.Is(err, ErrNotFound) {
+		t.Errorf("updating a missing song: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateWithDeletedGroup(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryStore()
+		testUpdateWithDeletedGroup(t, store.Songs(), store.Groups(), func(id uint) {
+			group := store.groups[id]
+			group.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			store.groups[id] = group
+		})
+	})
+
+	t.Run("database", func(t *testing.T) {
+		db := testDB(t)
+		testUpdateWithDeletedGroup(t, NewSongRepository(db), NewGroupRepository(db), func(id uint) {
+			if err := db.Delete(&models.Group{}, id).Error; err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+}
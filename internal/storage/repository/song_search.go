@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"effectiveMobileTask/internal/models"
+	"strings"
+	"unicode"
+)
+
+const headlineOptions = `HighlightAll=true, StartSel="` + HighlightStart + `", StopSel="` + HighlightStop + `"`
+
+type searchRow struct {
+	models.Song
+	Rank             float64
+	HighlightedTitle string
+	HighlightedText  string
+}
+
+func (r *songRepository) Search(ctx context.Context, query SearchQuery) ([]SearchResult, error) {
+	sql, params := searchSQL(query)
+
+	var rows []searchRow
+	err := r.db.WithContext(ctx).Raw(sql, params).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, SearchResult{
+			Song:             row.Song,
+			Rank:             row.Rank,
+			HighlightedTitle: row.HighlightedTitle,
+			HighlightedText:  row.HighlightedText,
+		})
+	}
+	return results, nil
+}
+
+// searchSQL builds the search statement. The tsquery is parsed with a single
+// text search configuration, the requested language or models.DefaultLanguage,
+// so that it is the same for every row and search_vector @@ query can use the
+// GIN index. Each song's own language is only used to highlight the matches.
+func searchSQL(query SearchQuery) (string, map[string]interface{}) {
+	config := query.Language
+	if config == "" {
+		config = models.DefaultLanguage
+	}
+
+	var tsquery string
+	text := query.Text
+	switch query.Mode {
+	case SearchModePhrase:
+		tsquery = "phraseto_tsquery(@config::regconfig, @text)"
+	case SearchModePrefix:
+		tsquery = "to_tsquery(@config::regconfig, @text)"
+		text = prefixQuery(text)
+	default:
+		tsquery = "websearch_to_tsquery(@config::regconfig, @text)"
+	}
+
+	sql := `SELECT songs.*, groups.name AS group_name,
+			ts_rank_cd(songs.search_vector, sq.query) AS rank,
+			ts_headline(songs.language, coalesce(songs.title, ''), sq.query, @options) AS highlighted_title,
+			ts_headline(songs.language, coalesce(songs.text, ''), sq.query, @options) AS highlighted_text
+		FROM songs
+		JOIN groups ON songs.group_id = groups.id
+		CROSS JOIN (SELECT ` + tsquery + ` AS query) sq
+		WHERE songs.deleted_at IS NULL AND songs.search_vector @@ sq.query`
+	if query.Language != "" {
+		sql += ` AND songs.language = @config::regconfig`
+	}
+	sql += ` ORDER BY rank DESC, songs.id LIMIT @limit OFFSET @offset`
+
+	return sql, map[string]interface{}{
+		"text":    text,
+		"config":  config,
+		"options": headlineOptions,
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+	}
+}
+
+// SearchTerms splits the query into lower-cased words.
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// prefixQuery turns "supermass bla" into "supermass:* & bla:*".
+func prefixQuery(text string) string {
+	terms := SearchTerms(text)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
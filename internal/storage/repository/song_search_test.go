@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"effectiveMobileTask/internal/models"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func TestSearchSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      SearchQuery
+		wantConfig string
+		wantText   string
+		wantFilter bool
+	}{
+		{"default", SearchQuery{Text: "black hole"}, models.DefaultLanguage, "black hole", false},
+		{"language", SearchQuery{Text: "holes", Language: "english"}, "english", "holes", true},
+		{"prefix", SearchQuery{Text: "super mass", Mode: SearchModePrefix}, models.DefaultLanguage, "super:* & mass:*", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params := searchSQL(tt.query)
+			if params["config"] != tt.wantConfig {
+				t.Errorf("config = %v, want %s", params["config"], tt.wantConfig)
+			}
+			if params["text"] != tt.wantText {
+				t.Errorf("text = %v, want %s", params["text"], tt.wantText)
+			}
+			if strings.Contains(sql, "LATERAL") || strings.Contains(sql, "tsquery(songs.language") {
+				t.Errorf("the tsquery depends on the row:\n%s", sql)
+			}
+			if got := strings.Contains(sql, "songs.language = @config"); got != tt.wantFilter {
+				t.Errorf("language filter = %t, want %t", got, tt.wantFilter)
+			}
+		})
+	}
+}
+
+// TestSearchUsesIndex checks the query plan against a real database.
+func TestSearchUsesIndex(t *testing.T) {
+	db := testDB(t)
+
+	for _, query := range []SearchQuery{
+		{Text: "black hole", Limit: 10},
+		{Text: "black hole", Language: "english", Limit: 10},
+	} {
+		sql, params := searchSQL(query)
+		var plan []string
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+				return err
+			}
+			return tx.Raw("EXPLAIN "+sql, params).Scan(&plan).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(strings.Join(plan, "\n"), "idx_songs_search_vector") {
+			t.Errorf("search with language %q does not use the index:\n%s", query.Language, strings.Join(plan, "\n"))
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"cmp"
+	"effectiveMobileTask/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SongSortField is a field songs can be ordered by.
+type SongSortField string
+
+const (
+	SortByID          SongSortField = "id"
+	SortByTitle       SongSortField = "title"
+	SortByGroup       SongSortField = "group"
+	SortByReleaseDate SongSortField = "release_date"
+	SortByCreatedAt   SongSortField = "created_at"
+	SortByUpdatedAt   SongSortField = "updated_at"
+)
+
+var sortColumns = map[SongSortField]string{
+	SortByID:          "songs.id",
+	SortByTitle:       "songs.title",
+	SortByGroup:       "groups.name",
+	SortByReleaseDate: "songs.release_date",
+	SortByCreatedAt:   "songs.created_at",
+	SortByUpdatedAt:   "songs.updated_at",
+}
+
+type SongSort struct {
+	Field SongSortField
+	Desc  bool
+}
+
+// DefaultSongSort orders songs by id, the order they were added in.
+var DefaultSongSort = []SongSort{{Field: SortByID}}
+
+// ParseSongSort parses a comma separated list of fields, each optionally
+// prefixed with "-" for descending order, e.g. "release_date,-title". The
+// result always ends with the id, so that the order is total and can be
+// used for cursors.
+func ParseSongSort(spec string) ([]SongSort, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultSongSort, nil
+	}
+
+	seen := make(map[SongSortField]bool)
+	keys := make([]SongSort, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		key := SongSort{Field: SongSortField(strings.TrimPrefix(part, "-")), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortColumns[key.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q", key.Field)
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("sort field %q is given twice", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+
+		// The id is unique, any key after it would never be looked at.
+		if key.Field == SortByID {
+			return keys, nil
+		}
+	}
+	return append(keys, SongSort{Field: SortByID}), nil
+}
+
+// FormatSongSort is the inverse of ParseSongSort.
+func FormatSongSort(keys []SongSort) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = string(key.Field)
+		if key.Desc {
+			parts[i] = "-" + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// NewSongCursor returns a cursor pointing at the song within a listing
+// ordered by keys.
+func NewSongCursor(song models.Song, keys []SongSort, before bool) SongCursor {
+	cursor := SongCursor{ID: song.ID, Before: before}
+	for _, key := range keys[:len(keys)-1] {
+		cursor.Values = append(cursor.Values, formatSortValue(sortValue(song, key.Field)))
+	}
+	return cursor
+}
+
+// values parses the cursor values for the given sort keys. The id, always
+// the last key, is taken from the cursor ID.
+func (c SongCursor) values(keys []SongSort) ([]any, error) {
+	if len(c.Values) != len(keys)-1 {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys[:len(keys)-1] {
+		value, err := parseSortValue(key.Field, c.Values[i])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value
+	}
+	values[len(keys)-1] = c.ID
+	return values, nil
+}
+
+func sortValue(song models.Song, field SongSortField) any {
+	switch field {
+	case SortByTitle:
+		return song.Title
+	case SortByGroup:
+		return song.GroupName
+	case SortByReleaseDate:
+		return song.ReleaseDate
+	case SortByCreatedAt:
+		return song.CreatedAt
+	case SortByUpdatedAt:
+		return song.UpdatedAt
+	}
+	return song.ID
+}
+
+func formatSortValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return value.(string)
+}
+
+func parseSortValue(field SongSortField, value string) (any, error) {
+	switch field {
+	case SortByReleaseDate, SortByCreatedAt, SortByUpdatedAt:
+		return time.Parse(time.RFC3339Nano, value)
+	case SortByID:
+		id, err := strconv.ParseUint(value, 10, 64)
+		return uint(id), err
+	}
+	return value, nil
+}
+
+// keysetCondition builds the condition selecting the songs after (or, with
+// before, preceding) the position given by values. For keys a, -b, id it is
+//
+//	a > ? OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+func keysetCondition(keys []SongSort, values []any, before bool) (string, []any) {
+	clauses := make([]string, 0, len(keys))
+	args := make([]any, 0)
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, sortColumns[keys[j].Field]+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := ">"
+		if key.Desc != before {
+			op = "<"
+		}
+		parts = append(parts, sortColumns[key.Field]+" "+op+" ?")
+		args = append(args, values[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// orderClause returns the ORDER BY clause for keys, reversed with reverse.
+func orderClause(keys []SongSort, reverse bool) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = sortColumns[key.Field]
+		if key.Desc != reverse {
+			parts[i] += " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// compareSongs compares a song with the position given by values, in the
+// order defined by keys.
+func compareSongs(song models.Song, keys []SongSort, values []any) int {
+	for i, key := range keys {
+		var result int
+		switch v := values[i].(type) {
+		case time.Time:
+			result = sortValue(song, key.Field).(time.Time).Compare(v)
+		case uint:
+			result = cmp.Compare(sortValue(song, key.Field).(uint), v)
+		case string:
+			result = cmp.Compare(sortValue(song, key.Field).(string), v)
+		}
+		if key.Desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func sortPosition(song models.Song, keys []SongSort) []any {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = sortValue(song, key.Field)
+	}
+	return values
+}
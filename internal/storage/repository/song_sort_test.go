@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"effectiveMobileTask/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSongSort(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []SongSort
+		wantErr bool
+	}{
+		{spec: "", want: DefaultSongSort},
+		{spec: "  ", want: DefaultSongSort},
+		{spec: "title", want: []SongSort{{Field: SortByTitle}, {Field: SortByID}}},
+		{spec: "release_date, -title", want: []SongSort{{Field: SortByReleaseDate}, {Field: SortByTitle, Desc: true}, {Field: SortByID}}},
+		{spec: "-id", want: []SongSort{{Field: SortByID, Desc: true}}},
+		{spec: "group,id,title", want: []SongSort{{Field: SortByGroup}, {Field: SortByID}}},
+		{spec: "name", wantErr: true},
+		{spec: "title,-title", wantErr: true},
+		{spec: "title,", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSongSort(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSongSort(%q) error = %v, want error %t", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSongSort(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+		if err == nil && tt.spec != "" && tt.spec != "  " {
+			if again, _ := ParseSongSort(FormatSongSort(got)); !reflect.DeepEqual(again, got) {
+				t.Errorf("FormatSongSort(%v) = %q does not parse back", got, FormatSongSort(got))
+			}
+		}
+	}
+}
+
+func TestSongCursorValues(t *testing.T) {
+	released := time.Date(2009, 9, 7, 0, 0, 0, 0, time.UTC)
+	song := models.Song{ID: 7, Title: "Uprising", GroupName: "Muse", ReleaseDate: released}
+	keys := []SongSort{{Field: SortByReleaseDate, Desc: true}, {Field: SortByGroup}, {Field: SortByID}}
+
+	cursor := NewSongCursor(song, keys, true)
+	if !reflect.DeepEqual(cursor.Values, []string{"2009-09-07T00:00:00Z", "Muse"}) || cursor.ID != 7 || !cursor.Before {
+		t.Fatalf("NewSongCursor = %+v", cursor)
+	}
+
+	values, err := cursor.values(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []any{released, "Muse", uint(7)}) {
+		t.Errorf("values = %v", values)
+	}
+	if compareSongs(song, keys, values) != 0 {
+		t.Error("song does not compare equal to its own cursor")
+	}
+
+	if _, err := cursor.values(DefaultSongSort); err != ErrInvalidCursor {
+		t.Errorf("values for another sort order: error = %v, want ErrInvalidCursor", err)
+	}
+	cursor.Values[0] = "yesterday"
+	if _, err := cursor.values(keys); err != ErrInvalidCursor {
+		t.Errorf("values with a malformed date: error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCompareSongs(t *testing.T) {
+	keys := []SongSort{{Field: SortByTitle, Desc: true}, {Field: SortByID}}
+	position := []any{"M", uint(5)}
+	tests := []struct {
+		song models.Song
+		want int
+	}{
+		{models.Song{ID: 1, Title: "Z"}, -1},
+		{models.Song{ID: 1, Title: "A"}, 1},
+		{models.Song{ID: 4, Title: "M"}, -1},
+		{models.Song{ID: 6, Title: "M"}, 1},
+		{models.Song{ID: 5, Title: "M"}, 0},
+	}
+	for _, tt := range tests {
+		if got := compareSongs(tt.song, keys, position); got != tt.want {
+			t.Errorf("compareSongs(%q #%d) = %d, want %d", tt.song.Title, tt.song.ID, got, tt.want)
+		}
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	keys := []SongSort{{Field: SortByTitle}, {Field: SortByGroup, Desc: true}, {Field: SortByID}}
+	sql, args := keysetCondition(keys, []any{"a", "b", uint(3)}, false)
+	wantSQL := "((songs.title > ?) OR (songs.title = ? AND groups.name < ?) OR (songs.title = ? AND groups.name = ? AND songs.id > ?))"
+	if sql != wantSQL {
+		t.Errorf("keysetCondition = %s, want %s", sql, wantSQL)
+	}
+	if want := []any{"a", "a", "b", "a", "b", uint(3)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("keysetCondition args = %v, want %v", args, want)
+	}
+	if got := orderClause(keys, true); got != "songs.title DESC, groups.name, songs.id DESC" {
+		t.Errorf("orderClause reversed = %s", got)
+	}
+}
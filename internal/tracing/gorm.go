@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// maxStatementLength keeps huge statements, such as batch inserts, out of
+// the spans.
+const maxStatementLength = 2000
+
+const parentContextKey = "tracing:parent_context"
+
+// GormPlugin creates a span for every query GORM runs. Statements are
+// recorded with placeholders, never with the values.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	c := db.Callback()
+	return errors.Join(
+		c.Create().Before("gorm:create").Register("tracing:before_create", beforeQuery("INSERT")),
+		c.Create().After("gorm:create").Register("tracing:after_create", afterQuery),
+		c.Query().Before("gorm:query").Register("tracing:before_query", beforeQuery("SELECT")),
+		c.Query().After("gorm:query").Register("tracing:after_query", afterQuery),
+		c.Update().Before("gorm:update").Register("tracing:before_update", beforeQuery("UPDATE")),
+		c.Update().After("gorm:update").Register("tracing:after_update", afterQuery),
+		c.Delete().Before("gorm:delete").Register("tracing:before_delete", beforeQuery("DELETE")),
+		c.Delete().After("gorm:delete").Register("tracing:after_delete", afterQuery),
+		c.Row().Before("gorm:row").Register("tracing:before_row", beforeQuery("ROW")),
+		c.Row().After("gorm:row").Register("tracing:after_row", afterQuery),
+		c.Raw().Before("gorm:raw").Register("tracing:before_raw", beforeQuery("RAW")),
+		c.Raw().After("gorm:raw").Register("tracing:after_raw", afterQuery),
+	)
+}
+
+func beforeQuery(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, _ := Tracer().Start(parent, "db "+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)))
+		tx.InstanceSet(parentContextKey, parent)
+		tx.Statement.Context = ctx
+	}
+}
+
+func afterQuery(tx *gorm.DB) {
+	span := trace.SpanFromContext(tx.Statement.Context)
+	if !span.IsRecording() {
+		restoreParent(tx)
+		return
+	}
+
+	span.SetAttributes(
+		semconv.DBCollectionName(tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if statement := tx.Statement.SQL.String(); statement != "" {
+		if len(statement) > maxStatementLength {
+			statement = statement[:maxStatementLength]
+		}
+		span.SetAttributes(semconv.DBQueryText(statement))
+	}
+	// A missing record is an answer, not a failure of the query.
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+	span.End()
+	restoreParent(tx)
+}
+
+// restoreParent puts back the context of the caller, so that the next query
+// run on the same statement does not become a child of the ended span.
+func restoreParent(tx *gorm.DB) {
+	if parent, ok := tx.InstanceGet(parentContextKey); ok {
+		tx.Statement.Context = parent.(context.Context)
+	}
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"effectiveMobileTask/config"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// exportedSpan holds the fields of a span written by the stdout exporter
+// that the tests look at.
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+}
+
+func TestSetupStdoutExporter(t *testing.T) {
+	previous, name := otel.GetTracerProvider(), serviceName
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		serviceName = name
+	})
+
+	file := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: ExporterStdout, ServiceName: "music-library-test", File: file, SampleRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled trace.SpanContext
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/songs/:id", func(c *gin.Context) {
+		_, span := Tracer().Start(c.Request.Context(), "load song")
+		handled = span.SpanContext()
+		span.End()
+		c.Status(http.StatusOK)
+	})
+
+	// The trace of the caller is continued.
+	const traceID, parentID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	req := httptest.NewRequest(http.MethodGet, "/songs/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if handled.TraceID().String() != traceID {
+		t.Errorf("handler trace ID = %s, want %s", handled.TraceID(), traceID)
+	}
+
+	output, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := map[string]exportedSpan{}
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+	for decoder.More() {
+		var span exportedSpan
+		if err := decoder.Decode(&span); err != nil {
+			t.Fatalf("invalid span in %s: %v", output, err)
+		}
+		spans[span.Name] = span
+	}
+
+	request, ok := spans["/songs/:id"]
+	if !ok || request.SpanContext.TraceID != traceID || request.Parent.SpanID != parentID {
+		t.Errorf("request span = %+v, want a child of %s in trace %s", request, parentID, traceID)
+	}
+	child, ok := spans["load song"]
+	if !ok || child.SpanContext.TraceID != traceID || child.Parent.SpanID != request.SpanContext.SpanID {
+		t.Errorf("handler span = %+v, want a child of the request span", child)
+	}
+	if !strings.Contains(string(output), "music-library-test") {
+		t.Errorf("spans do not name the service: %s", output)
+	}
+}
+
+func TestSetupExporters(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: ExporterNone})
+	if err != nil || shutdown(context.Background()) != nil {
+		t.Errorf("Setup(none) = %v", err)
+	}
+
+	if _, err := Setup(context.Background(), config.TracingConfig{Exporter: "jaeger"}); err == nil {
+		t.Error("Setup accepted an unknown exporter")
+	}
+
+	file := filepath.Join(t.TempDir(), "missing", "spans.json")
+	if _, err := Setup(context.Background(), config.TracingConfig{Exporter: ExporterStdout, File: file}); err == nil {
+		t.Error("Setup accepted a trace file in a missing directory")
+	}
+}
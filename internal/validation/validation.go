@@ -0,0 +1,103 @@
+// Package validation registers the rules request bodies are checked with,
+// in addition to the built-in ones of the validator, and describes their
+// violations to clients.
+//
+// Rules are declared in binding tags:
+//
+//	notblank     the string has a character other than white space
+//	releasedate  a DD.MM.YYYY or YYYY-MM-DD date
+//	language     a supported text search configuration
+//	httpurl      an absolute http or https URL
+package validation
+
+import (
+	"effectiveMobileTask/internal/models"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		panic("validation: unexpected gin validator engine")
+	}
+
+	// Report fields by their JSON names rather than the Go ones.
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	rules := map[string]validator.Func{
+		"notblank":    validators.NotBlank,
+		"releasedate": func(fl validator.FieldLevel) bool { return IsReleaseDate(fl.Field().String()) },
+		"language":    func(fl validator.FieldLevel) bool { return models.IsSupportedLanguage(fl.Field().String()) },
+		"httpurl":     func(fl validator.FieldLevel) bool { return IsHTTPURL(fl.Field().String()) },
+	}
+	for tag, rule := range rules {
+		if err := validate.RegisterValidation(tag, rule); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func IsReleaseDate(value string) bool {
+	_, err := models.ParseReleaseDate(value)
+	return err == nil
+}
+
+func IsHTTPURL(value string) bool {
+	link, err := url.ParseRequestURI(value)
+	return err == nil && (link.Scheme == "http" || link.Scheme == "https") && link.Host != ""
+}
+
+// Field returns the path of the invalid field without the name of the
+// request struct, e.g. "group" rather than "songRequest.group".
+func Field(fieldErr validator.FieldError) string {
+	_, path, ok := strings.Cut(fieldErr.Namespace(), ".")
+	if !ok {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// Message describes the violated rule.
+func Message(fieldErr validator.FieldError) string {
+	isString := fieldErr.Kind() == reflect.String
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+		}
+		return "must be at most " + fieldErr.Param()
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters long", fieldErr.Param())
+		}
+		return "must be at least " + fieldErr.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "releasedate":
+		return "expected DD.MM.YYYY or YYYY-MM-DD"
+	case "language":
+		return fmt.Sprintf("unsupported language %q", fieldErr.Value())
+	case "httpurl":
+		return "expected an http or https URL"
+	}
+	return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+}
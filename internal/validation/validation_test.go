@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"testing"
+)
+
+type testLyrics struct {
+	Language string `json:"language" binding:"omitempty,language"`
+}
+
+type testRequest struct {
+	Name        string     `json:"name" binding:"required,notblank,max=5"`
+	Count       int        `json:"count" binding:"max=3"`
+	ReleaseDate string     `json:"release_date" binding:"omitempty,releasedate"`
+	Link        string     `json:"link" binding:"omitempty,httpurl"`
+	Lyrics      testLyrics `json:"lyrics"`
+}
+
+// violations binds the body and returns the message of each invalid field.
+func violations(t *testing.T, body string) map[string]string {
+	t.Helper()
+	var request testRequest
+	err := binding.JSON.BindBody([]byte(body), &request)
+	if err == nil {
+		return map[string]string{}
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("BindBody(%s) failed with %v, want validation errors", body, err)
+	}
+	got := make(map[string]string)
+	for _, fieldErr := range fieldErrs {
+		got[Field(fieldErr)] = Message(fieldErr)
+	}
+	return got
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{
+			name: "valid",
+			body: `{"name": "Muse", "count": 3, "release_date": "2006-07-16", "link": "https://example.com/a", "lyrics": {"language": "english"}}`,
+			want: map[string]string{},
+		},
+		{
+			name: "several violations in one body",
+			body: `{"name": " ", "count": 4, "release_date": "16/07/2006", "link": "ftp://example.com", "lyrics": {"language": "klingon"}}`,
+			want: map[string]string{
+				"name":            "must not be blank",
+				"count":           "must be at most 3",
+				"release_date":    "expected DD.MM.YYYY or YYYY-MM-DD",
+				"link":            "expected an http or https URL",
+				"lyrics.language": `unsupported language "klingon"`,
+			},
+		},
+		{
+			name: "missing",
+			body: `{}`,
+			want: map[string]string{"name": "is required"},
+		},
+		{
+			name: "string too long",
+			body: `{"name": "Muse Muse"}`,
+			want: map[string]string{"name": "must be at most 5 characters long"},
+		},
+		{
+			name: "max counts characters rather than bytes",
+			body: `{"name": "Мьюз"}`,
+			want: map[string]string{},
+		},
+		{
+			name: "dotted release date",
+			body: `{"name": "Muse", "release_date": "16.07.2006"}`,
+			want: map[string]string{},
+		},
+		{
+			name: "impossible release date",
+			body: `{"name": "Muse", "release_date": "31.02.2006"}`,
+			want: map[string]string{"release_date": "expected DD.MM.YYYY or YYYY-MM-DD"},
+		},
+		{
+			name: "relative link",
+			body: `{"name": "Muse", "link": "/watch?v=1"}`,
+			want: map[string]string{"link": "expected an http or https URL"},
+		},
+		{
+			name: "link without host",
+			body: `{"name": "Muse", "link": "http://"}`,
+			want: map[string]string{"link": "expected an http or https URL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violations(t, tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHTTPURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/watch?v=1": true,
+		"http://localhost:8080":         true,
+		"HTTPS://example.com":           true,
+		"mailto:muse@example.com":       false,
+		"example.com":                   false,
+		"":                              false,
+	}
+	for value, want := range tests {
+		if got := IsHTTPURL(value); got != want {
+			t.Errorf("IsHTTPURL(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package version holds build information. Version, Commit and BuildTime
+// are set at link time:
+//
+//	go build -ldflags "-X effectiveMobileTask/internal/version.Version=v1.2.0 \
+//		-X effectiveMobileTask/internal/version.Commit=$(git rev-parse HEAD) \
+//		-X effectiveMobileTask/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version   string `json:"version" example:"v1.2.0"`
+	Commit    string `json:"commit,omitempty" example:"4735636"`
+	BuildTime string `json:"build_time,omitempty" example:"2024-09-01T12:00:00Z"`
+	GoVersion string `json:"go_version" example:"go1.23.2"`
+}
+
+// Get returns the build information. Without a Commit set by ldflags, the
+// revision go build stamps into the binary is used, when there is one.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if info.Commit != "" {
+		return info
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	modified := false
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	return info
+}
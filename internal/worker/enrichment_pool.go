@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/internal/tracing"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type EnrichmentPoolConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	StaleAfter   time.Duration
+}
+
+// EnrichmentPool runs background workers that pick enrichment jobs from the
+// job table, look the song details up and store them on the song.
+type EnrichmentPool struct {
+	jobs     repository.JobRepository
+	songs    repository.SongRepository
+	enricher enrichment.SongEnricher
+	cfg      EnrichmentPoolConfig
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewEnrichmentPool(jobs repository.JobRepository, songs repository.SongRepository, enricher enrichment.SongEnricher, cfg EnrichmentPoolConfig) *EnrichmentPool {
+	return &EnrichmentPool{
+		jobs:     jobs,
+		songs:    songs,
+		enricher: enricher,
+		cfg:      cfg,
+	}
+}
+
+// Start starts the workers. With no workers, or no poll interval, the pool
+// stays off and queued jobs are not run.
+func (p *EnrichmentPool) Start(ctx context.Context) {
+	if p.cfg.Workers <= 0 || p.cfg.PollInterval <= 0 {
+		logger.Info("enrichment workers disabled", slog.Int("workers", p.cfg.Workers), slog.Duration("poll_interval", p.cfg.PollInterval))
+		return
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go func(id int) {
+			defer p.wg.Done()
+			p.run(ctx, id)
+		}(i + 1)
+	}
+	logger.Info("enrichment workers started", slog.Int("workers", p.cfg.Workers))
+}
+
+// Stop asks the workers to finish and waits until they exit.
+func (p *EnrichmentPool) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+	logger.Info("enrichment workers stopped")
+}
+
+func (p *EnrichmentPool) run(ctx context.Context, id int) {
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going back to sleep.
+		for ctx.Err() == nil && p.processNext(ctx, id) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext handles a single job and reports whether one was found.
+func (p *EnrichmentPool) processNext(ctx context.Context, workerID int) bool {
+	job, err := p.jobs.ClaimNext(ctx, time.Now().Add(-p.cfg.StaleAfter))
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) && ctx.Err() == nil {
+			logger.Error("failed to claim enrichment job", slog.Int("worker", workerID), slog.Any("error", err))
+		}
+		return false
+	}
+
+	ctx = logger.WithAttrs(ctx, slog.Int("worker", workerID), slog.Any("job_id", job.ID), slog.Any("song_id", job.SongID))
+	log := logger.FromContext(ctx)
+
+	err = p.enrich(ctx, job)
+	if err == nil {
+		if err := p.jobs.Complete(ctx, job.ID); err != nil {
+			log.Error("failed to complete enrichment job", slog.Any("error", err))
+		}
+		log.Info("song enriched")
+		return true
+	}
+
+	if ctx.Err() != nil {
+		// Shutting down: leave the job to be picked up again once it goes stale.
+		return false
+	}
+
+	retry := job.Attempts < p.cfg.MaxAttempts &&
+		!errors.Is(err, enrichment.ErrNotFound) &&
+		!errors.Is(err, repository.ErrNotFound)
+	var retryAt *time.Time
+	if retry {
+		next := time.Now().Add(p.cfg.RetryDelay * time.Duration(job.Attempts))
+		retryAt = &next
+	}
+
+	if err := p.jobs.Fail(ctx, job.ID, err.Error(), retryAt); err != nil {
+		log.Error("failed to record enrichment job failure", slog.Any("error", err))
+	}
+
+	if retry {
+		log.Info("enrichment failed, job rescheduled", slog.Any("error", err), slog.Int("attempts", job.Attempts))
+		return true
+	}
+
+	log.Error("enrichment failed", slog.Any("error", err), slog.Int("attempts", job.Attempts))
+	p.markSongFailed(ctx, job.SongID)
+	return true
+}
+
+func (p *EnrichmentPool) enrich(ctx context.Context, job *models.EnrichmentJob) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "enrichment.job", trace.WithAttributes(
+		attribute.Int64("job.id", int64(job.ID)),
+		attribute.Int64("song.id", int64(job.SongID)),
+		attribute.Int("job.attempts", job.Attempts)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	detail, err := p.enricher.Enrich(ctx, job.Group, job.Song)
+	if err != nil {
+		return err
+	}
+
+	song, err := p.songs.GetByID(ctx, job.SongID)
+	if err != nil {
+		return err
+	}
+
+	// Replacing the text would drop the synced lyrics uploaded for it, and
+	// with them any edits made to the text along with the upload.
+	if _, err := p.songs.SyncedLyrics(ctx, song.ID); err == nil {
+		if detail.Text != "" && detail.Text != song.Text {
+			logger.FromContext(ctx).Info("song has synced lyrics, keeping its text")
+		}
+		detail.Text = ""
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	// Like POST /info, a release date that does not parse does not fail the
+	// enrichment: the other fields are kept and a song without a release
+	// date gets today's.
+	if err := enrichment.Apply(song, detail); err != nil {
+		logger.FromContext(ctx).Error("failed to parse release date", slog.Any("error", err))
+		if song.ReleaseDate.IsZero() {
+			song.ReleaseDate = time.Now()
+		}
+	}
+	song.EnrichmentStatus = models.EnrichmentStatusEnriched
+
+	return p.songs.Update(ctx, song)
+}
+
+func (p *EnrichmentPool) markSongFailed(ctx context.Context, songID uint) {
+	song, err := p.songs.GetByID(ctx, songID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to load song for enrichment failure", slog.Any("error", err))
+		return
+	}
+
+	song.EnrichmentStatus = models.EnrichmentStatusFailed
+	if err := p.songs.Update(ctx, song); err != nil {
+		logger.FromContext(ctx).Error("failed to mark song enrichment as failed", slog.Any("error", err))
+	}
+}
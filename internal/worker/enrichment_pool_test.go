@@ -0,0 +1,267 @@
+package worker
+
+import (
+	"context"
+	"effectiveMobileTask/internal/audit"
+	"effectiveMobileTask/internal/enrichment"
+	"effectiveMobileTask/internal/models"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	_ = logger.Setup("error", logger.FormatJSON, io.Discard)
+}
+
+// stubEnricher returns a fixed detail or error and counts its calls.
+type stubEnricher struct {
+	detail models.SongDetail
+	err    error
+	calls  atomic.Int32
+}
+
+func (e *stubEnricher) Name() string {
+	return "stub"
+}
+
+func (e *stubEnricher) Enrich(context.Context, string, string) (models.SongDetail, error) {
+	e.calls.Add(1)
+	return e.detail, e.err
+}
+
+// newTestPool returns a pool over a memory store holding one pending song
+// with a queued job.
+func newTestPool(t *testing.T, enricher enrichment.SongEnricher, cfg EnrichmentPoolConfig) (*EnrichmentPool, *repository.MemoryStore, *models.Song, *models.EnrichmentJob) {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	group, err := store.Groups().FirstOrCreate(context.Background(), "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	song := &models.Song{GroupId: group.ID, Title: "Uprising", EnrichmentStatus: models.EnrichmentStatusPending}
+	job := &models.EnrichmentJob{Group: "Muse", Song: "Uprising"}
+	if err := store.Songs().CreateWithJob(context.Background(), song, job); err != nil {
+		t.Fatal(err)
+	}
+	return NewEnrichmentPool(store.Jobs(), store.Songs(), enricher, cfg), store, song, job
+}
+
+func TestEnrichmentPoolProcessNext(t *testing.T) {
+	detail := models.SongDetail{ReleaseDate: "16.07.2009", Text: "Paranoia is in bloom", Link: "https://example.com/uprising"}
+
+	tests := []struct {
+		name        string
+		enricher    *stubEnricher
+		maxAttempts int
+		wantJob     string
+		wantSong    string
+		wantRetry   bool
+	}{
+		{
+			name:        "enriched",
+			enricher:    &stubEnricher{detail: detail},
+			maxAttempts: 3,
+			wantJob:     models.JobStatusDone,
+			wantSong:    models.EnrichmentStatusEnriched,
+		},
+		{
+			name:        "upstream error is retried",
+			enricher:    &stubEnricher{err: errors.New("upstream down")},
+			maxAttempts: 3,
+			wantJob:     models.JobStatusQueued,
+			wantSong:    models.EnrichmentStatusPending,
+			wantRetry:   true,
+		},
+		{
+			name:        "upstream error on the last attempt",
+			enricher:    &stubEnricher{err: errors.New("upstream down")},
+			maxAttempts: 1,
+			wantJob:     models.JobStatusFailed,
+			wantSong:    models.EnrichmentStatusFailed,
+		},
+		{
+			name:        "not found is not retried",
+			enricher:    &stubEnricher{err: enrichment.ErrNotFound},
+			maxAttempts: 3,
+			wantJob:     models.JobStatusFailed,
+			wantSong:    models.EnrichmentStatusFailed,
+		},
+		{
+			name:        "invalid release date keeps the other fields",
+			enricher:    &stubEnricher{detail: models.SongDetail{ReleaseDate: "someday", Text: detail.Text}},
+			maxAttempts: 3,
+			wantJob:     models.JobStatusDone,
+			wantSong:    models.EnrichmentStatusEnriched,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, store, song, job := newTestPool(t, tt.enricher, EnrichmentPoolConfig{
+				MaxAttempts: tt.maxAttempts,
+				RetryDelay:  time.Hour,
+				StaleAfter:  time.Hour,
+			})
+			ctx := context.Background()
+
+			if !pool.processNext(ctx, 1) {
+				t.Fatal("processNext found no job")
+			}
+
+			gotJob, err := store.Jobs().GetByID(ctx, job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotJob.Status != tt.wantJob {
+				t.Errorf("job status = %q, want %q", gotJob.Status, tt.wantJob)
+			}
+			if gotJob.Attempts != 1 {
+				t.Errorf("job attempts = %d, want 1", gotJob.Attempts)
+			}
+			if retried := gotJob.RunAt.After(time.Now()); retried != tt.wantRetry {
+				t.Errorf("job rescheduled = %v, want %v", retried, tt.wantRetry)
+			}
+			if tt.wantJob != models.JobStatusDone && gotJob.LastError == "" {
+				t.Error("job has no last error")
+			}
+
+			gotSong, err := store.Songs().GetByID(ctx, song.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSong.EnrichmentStatus != tt.wantSong {
+				t.Errorf("song enrichment status = %q, want %q", gotSong.EnrichmentStatus, tt.wantSong)
+			}
+			if tt.wantSong == models.EnrichmentStatusEnriched {
+				if gotSong.Text != tt.enricher.detail.Text {
+					t.Errorf("song text = %q, want %q", gotSong.Text, tt.enricher.detail.Text)
+				}
+				if gotSong.ReleaseDate.IsZero() {
+					t.Error("song has no release date")
+				}
+			}
+
+			// A rescheduled job is not due yet, so there is nothing left.
+			if pool.processNext(ctx, 1) {
+				t.Error("processNext found a second job")
+			}
+		})
+	}
+}
+
+func TestEnrichmentPoolStartStop(t *testing.T) {
+	enricher := &stubEnricher{detail: models.SongDetail{Text: "Paranoia is in bloom"}}
+	pool, store, song, job := newTestPool(t, enricher, EnrichmentPoolConfig{
+		Workers:      2,
+		PollInterval: 10 * time.Millisecond,
+		MaxAttempts:  3,
+		StaleAfter:   time.Hour,
+	})
+
+	pool.Start(context.Background())
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := store.Jobs().GetByID(context.Background(), job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status == models.JobStatusDone {
+			break
+		}
+		if time.Now().After(deadline) {
+			pool.Stop()
+			t.Fatalf("job still %q after 5s", got.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	// Jobs queued after Stop are left alone.
+	later := &models.EnrichmentJob{SongID: song.ID, Group: "Muse", Song: "Uprising"}
+	if err := store.Jobs().Enqueue(context.Background(), later); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got, _ := store.Jobs().GetByID(context.Background(), later.ID); got.Status != models.JobStatusQueued {
+		t.Errorf("job queued after Stop is %q", got.Status)
+	}
+	if calls := enricher.calls.Load(); calls != 1 {
+		t.Errorf("enricher called %d times, want 1", calls)
+	}
+}
+
+func TestEnrichmentPoolDisabled(t *testing.T) {
+	for _, cfg := range []EnrichmentPoolConfig{
+		{Workers: 2, PollInterval: 0},
+		{Workers: 2, PollInterval: -time.Second},
+		{Workers: 0, PollInterval: time.Second},
+	} {
+		pool, _, _, _ := newTestPool(t, &stubEnricher{}, cfg)
+		// Start must not panic on a ticker without a positive interval.
+		pool.Start(context.Background())
+		pool.Stop()
+	}
+
+	// Stop without Start does nothing.
+	pool, _, _, _ := newTestPool(t, &stubEnricher{}, EnrichmentPoolConfig{})
+	pool.Stop()
+}
+
+func TestEnrichmentPoolRecordsSystemActor(t *testing.T) {
+	enricher := &stubEnricher{detail: models.SongDetail{Text: "Paranoia is in bloom"}}
+	pool, store, song, _ := newTestPool(t, enricher, EnrichmentPoolConfig{MaxAttempts: 3, StaleAfter: time.Hour})
+	ctx := context.Background()
+	if !pool.processNext(ctx, 1) {
+		t.Fatal("processNext found no job")
+	}
+
+	revisions, err := store.Revisions().List(ctx, models.RevisionEntitySong, song.ID, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].Action != models.RevisionActionUpdate || revisions[0].Actor != audit.System {
+		t.Errorf("latest revision = %+v, want an update by %s", revisions, audit.System)
+	}
+}
+
+func TestEnrichmentPoolKeepsSyncedLyrics(t *testing.T) {
+	enricher := &stubEnricher{detail: models.SongDetail{Text: "upstream text", Link: "https://example.com/uprising"}}
+	pool, store, song, _ := newTestPool(t, enricher, EnrichmentPoolConfig{MaxAttempts: 3, StaleAfter: time.Hour})
+	ctx := context.Background()
+
+	song.Text = "Paranoia is in bloom"
+	synced := &models.SyncedLyrics{Lines: []models.TimedLine{{TimeMs: 1000, Text: song.Text}}}
+	if err := store.Songs().SaveSyncedLyrics(ctx, song, synced); err != nil {
+		t.Fatal(err)
+	}
+
+	if !pool.processNext(ctx, 1) {
+		t.Fatal("processNext found no job")
+	}
+
+	got, err := store.Songs().GetByID(ctx, song.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "Paranoia is in bloom" || got.Link != enricher.detail.Link || got.EnrichmentStatus != models.EnrichmentStatusEnriched {
+		t.Errorf("song = %q, %q, %s; want the uploaded text and the enriched link", got.Text, got.Link, got.EnrichmentStatus)
+	}
+	if _, err := store.Songs().SyncedLyrics(ctx, song.ID); err != nil {
+		t.Errorf("synced lyrics lost: %v", err)
+	}
+}
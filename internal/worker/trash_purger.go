@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"effectiveMobileTask/internal/storage/repository"
+	"effectiveMobileTask/lib/logger"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// TrashPurger periodically removes songs and groups that have been in the
+// trash for longer than the retention period.
+type TrashPurger struct {
+	songs     repository.SongRepository
+	groups    repository.GroupRepository
+	retention time.Duration
+	interval  time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTrashPurger(songs repository.SongRepository, groups repository.GroupRepository, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		songs:     songs,
+		groups:    groups,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+func (p *TrashPurger) Start(ctx context.Context) {
+	if p.interval <= 0 {
+		logger.Info("trash purge disabled")
+		return
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, _, err := repository.PurgeTrash(ctx, p.songs, p.groups, time.Now().Add(-p.retention)); err != nil && ctx.Err() == nil {
+				logger.Error("failed to purge trash", slog.Any("error", err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("trash purge started", slog.Duration("retention", p.retention), slog.Duration("interval", p.interval))
+}
+
+func (p *TrashPurger) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+}
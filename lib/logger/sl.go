@@ -1,12 +1,72 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/trace"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+)
+
+// Formats for Setup.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
 )
 
 var Logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
+// Setup replaces Logger with one writing records of the given level and
+// above to output in the given format.
+func Setup(level, format string, output io.Writer) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	options := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case FormatJSON, "":
+		Logger = slog.New(slog.NewJSONHandler(output, options))
+	case FormatText:
+		Logger = slog.New(slog.NewTextHandler(output, options))
+	default:
+		return fmt.Errorf("invalid log format %q", format)
+	}
+	return nil
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx carrying attrs, which FromContext adds to
+// every record logged with that context.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	previous, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	combined := make([]slog.Attr, 0, len(previous)+len(attrs))
+	combined = append(append(combined, previous...), attrs...)
+	return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// FromContext returns Logger with the attributes stored in ctx by WithAttrs
+// and, when ctx carries a valid span context, sampled or not, the trace and
+// span IDs.
+func FromContext(ctx context.Context) *slog.Logger {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	args := make([]any, 0, len(attrs)+2)
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		args = append(args, slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	if len(args) == 0 {
+		return Logger
+	}
+	return Logger.With(args...)
+}
+
 func Info(msg string, args ...interface{}) {
 	Logger.Info(msg, args...)
 }
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// capture makes Logger write JSON records to the returned buffer for the
+// rest of the test.
+func capture(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	previous := Logger
+	t.Cleanup(func() { Logger = previous })
+	var b bytes.Buffer
+	if err := Setup("debug", FormatJSON, &b); err != nil {
+		t.Fatal(err)
+	}
+	return &b
+}
+
+// lastRecord decodes the last record written to b.
+func lastRecord(t *testing.T, b *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &record); err != nil {
+		t.Fatalf("invalid log record %s: %v", b, err)
+	}
+	return record
+}
+
+func TestSetup(t *testing.T) {
+	previous := Logger
+	t.Cleanup(func() { Logger = previous })
+
+	var b bytes.Buffer
+	if err := Setup("warn", FormatText, &b); err != nil {
+		t.Fatal(err)
+	}
+	Logger.Info("hidden")
+	Logger.Warn("shown", "song_id", 1)
+	if got := b.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "level=WARN msg=shown song_id=1") {
+		t.Errorf("text output = %q", got)
+	}
+
+	if err := Setup("verbose", FormatJSON, &b); err == nil {
+		t.Error("Setup accepted an invalid level")
+	}
+	if err := Setup("info", "xml", &b); err == nil {
+		t.Error("Setup accepted an invalid format")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	logs := capture(t)
+
+	FromContext(context.Background()).Info("plain")
+	if record := lastRecord(t, logs); len(record) != 3 {
+		t.Errorf("record without attributes = %v", record)
+	}
+
+	ctx := WithAttrs(context.Background(), slog.String("request_id", "req-42"))
+	ctx = WithAttrs(ctx, slog.Int("song_id", 7))
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	// The IDs are logged for spans that are not sampled too.
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	FromContext(ctx).Info("traced")
+	record := lastRecord(t, logs)
+	want := map[string]any{"request_id": "req-42", "song_id": 7.0, "trace_id": traceID.String(), "span_id": spanID.String()}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+
+	// An invalid span context adds no IDs.
+	ctx = trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID}))
+	FromContext(ctx).Info("invalid span")
+	if record := lastRecord(t, logs); record["trace_id"] != nil || record["span_id"] != nil {
+		t.Errorf("record with an invalid span context = %v", record)
+	}
+}